@@ -0,0 +1 @@
+alter table orders drop column if exists status;
@@ -0,0 +1,3 @@
+alter table orders
+    add column if not exists status varchar(32) not null default 'pending'
+    check (status in ('pending', 'confirmed', 'paid', 'shipped', 'delivered', 'cancelled'));
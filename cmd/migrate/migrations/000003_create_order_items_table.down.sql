@@ -0,0 +1,14 @@
+alter table orders
+    add column if not exists item varchar(500),
+    add column if not exists quantity integer check (quantity > 0);
+
+update orders o
+set item = i.item, quantity = i.quantity
+from order_items i
+where i.order_id = o.id and i.position = 0;
+
+alter table orders
+    alter column item set not null,
+    alter column quantity set not null;
+
+drop table if exists order_items;
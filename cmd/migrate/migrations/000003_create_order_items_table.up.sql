@@ -0,0 +1,15 @@
+create table if not exists order_items (
+    order_id uuid not null references orders (id) on delete cascade,
+    position integer not null check (position >= 0),
+    item varchar(500) not null,
+    quantity integer not null check (quantity > 0),
+    primary key (order_id, position)
+);
+
+insert into order_items (order_id, position, item, quantity)
+select id, 0, item, quantity
+from orders;
+
+alter table orders
+    drop column if exists item,
+    drop column if exists quantity;
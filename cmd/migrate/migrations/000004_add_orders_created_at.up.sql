@@ -0,0 +1,6 @@
+alter table orders
+    add column if not exists created_at timestamptz not null default now();
+
+create index if not exists orders_created_at_id_idx on orders (created_at, id);
+create index if not exists orders_status_idx on orders (status);
+create index if not exists order_items_item_idx on order_items (item);
@@ -0,0 +1,2 @@
+alter table orders
+    add column if not exists version bigint not null default 1 check (version > 0);
@@ -0,0 +1,11 @@
+create table if not exists order_outbox (
+    id bigserial primary key,
+    event_id uuid not null unique,
+    aggregate_id uuid not null,
+    event_type varchar(64) not null,
+    payload bytea not null,
+    created_at timestamptz not null default now(),
+    dispatched_at timestamptz
+);
+
+create index if not exists order_outbox_pending_idx on order_outbox (id) where dispatched_at is null;
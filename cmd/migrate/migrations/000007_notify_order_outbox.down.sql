@@ -0,0 +1,2 @@
+drop trigger if exists order_outbox_notify on order_outbox;
+drop function if exists notify_order_outbox();
@@ -0,0 +1,10 @@
+create or replace function notify_order_outbox() returns trigger as $$
+begin
+    perform pg_notify('order_events', new.id::text);
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger order_outbox_notify
+    after insert on order_outbox
+    for each row execute function notify_order_outbox();
@@ -0,0 +1 @@
+drop index if exists order_outbox_dispatched_idx;
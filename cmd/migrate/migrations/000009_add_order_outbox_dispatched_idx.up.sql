@@ -0,0 +1 @@
+create index if not exists order_outbox_dispatched_idx on order_outbox (dispatched_at) where dispatched_at is not null;
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"orderservice/internal/config"
+	"orderservice/internal/logger"
 	"orderservice/internal/server"
+	"orderservice/internal/tracing"
 )
 
 func main() {
@@ -16,20 +20,66 @@ func main() {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
-	srv := server.New(cfg)
-	srv.RegisterServices()
+	appLogger, err := logger.New(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		log.Fatalf("failed to set up logger: %v", err)
+	}
+	slog.SetDefault(appLogger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     cfg.TracingExporter,
+		OTLPEndpoint: cfg.TracingOTLPEndpoint,
+		OTLPInsecure: cfg.TracingOTLPInsecure,
+		FilePath:     cfg.TracingFile,
+		SampleRatio:  cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+
+	srv, err := server.New(cfg)
+	if err != nil {
+		log.Fatalf("failed to create server: %v", err)
+	}
+	if err := srv.RegisterServices(); err != nil {
+		slog.Error("failed to register services", slog.Any("error", err))
+		os.Exit(1)
+	}
 
+	errCh := make(chan error, 1)
 	go func() {
-		if err := srv.Start(); err != nil {
-			log.Fatalf("failed to start server: %v", err)
-		}
+		errCh <- srv.Start()
 	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
 
-	log.Println("shutting down server...")
-	srv.Stop()
-	log.Println("server stopped")
+	exitCode := 0
+	select {
+	case <-quit:
+	case err := <-errCh:
+		slog.Error("server failed", slog.Any("error", err))
+		exitCode = 1
+	}
+
+	slog.Info("shutting down server")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Stop(ctx); err != nil {
+		slog.Error("server shutdown incomplete", slog.Any("error", err))
+		exitCode = 1
+	} else {
+		slog.Info("server stopped")
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("failed to flush traces", slog.Any("error", err))
+	}
+
+	if exitCode != 0 {
+		cancel()
+		os.Exit(exitCode)
+	}
 }
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrMissingToken   = errors.New("missing bearer token")
+	ErrInvalidToken   = errors.New("invalid bearer token")
+	ErrNoVerification = errors.New("no token verification key configured")
+)
+
+type Config struct {
+	HS256Secret string
+	JWKS        *JWKS
+	Issuer      string
+	Audience    string
+}
+
+type Authenticator struct {
+	hs256Secret []byte
+	jwks        *JWKS
+	parser      *jwt.Parser
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+
+	// Scope is the space-delimited OAuth 2.0 scope claim (RFC 8693).
+	Scope string `json:"scope"`
+
+	Roles []string `json:"roles"`
+}
+
+func NewAuthenticator(config *Config) (*Authenticator, error) {
+	var methods []string
+	if config.HS256Secret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if config.JWKS != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, ErrNoVerification
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(config.Audience))
+	}
+
+	return &Authenticator{
+		hs256Secret: []byte(config.HS256Secret),
+		jwks:        config.JWKS,
+		parser:      jwt.NewParser(opts...),
+	}, nil
+}
+
+// Authenticate verifies the value of an Authorization header and returns
+// the principal it identifies.
+func (a *Authenticator) Authenticate(ctx context.Context, authorization string) (*Principal, error) {
+	scheme, token, found := strings.Cut(authorization, " ")
+	if authorization == "" {
+		return nil, ErrMissingToken
+	}
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, fmt.Errorf("%w: expected bearer scheme", ErrInvalidToken)
+	}
+
+	var c claims
+	_, err := a.parser.ParseWithClaims(token, &c, func(t *jwt.Token) (any, error) {
+		switch t.Method.Alg() {
+		case jwt.SigningMethodHS256.Alg():
+			return a.hs256Secret, nil
+		case jwt.SigningMethodRS256.Alg():
+			kid, _ := t.Header["kid"].(string)
+			return a.jwks.Key(ctx, kid)
+		default:
+			return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if c.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return &Principal{
+		Subject: c.Subject,
+		Scopes:  strings.Fields(c.Scope),
+		Roles:   c.Roles,
+	}, nil
+}
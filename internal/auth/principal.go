@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+const (
+	ScopeOrdersRead  = "orders:read"
+	ScopeOrdersWrite = "orders:write"
+
+	// RoleAdmin grants access to the orders of every customer.
+	RoleAdmin = "admin"
+)
+
+// Principal is the authenticated caller. Its Subject is the customer id
+// that owns the orders it creates.
+type Principal struct {
+	Subject string
+	Scopes  []string
+	Roles   []string
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+func (p *Principal) IsAdmin() bool {
+	return slices.Contains(p.Roles, RoleAdmin)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
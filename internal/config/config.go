@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,12 +17,57 @@ type Config struct {
 	EnableHTTPHandler    bool
 	HTTPPort             int
 	LogLevel             string
+	LogFormat            string
 	DBHost               string
 	DBPort               int
 	DBUser               string
 	DBPassword           string
 	DBName               string
 	RedisURI             string
+	StorageBackend       string
+	SQLitePath           string
+	CacheEnable          bool
+	CacheTTL             time.Duration
+	CacheStore           string
+	CacheSize            int
+	CacheKeyPrefix       string
+	CacheL1Enable        bool
+	CacheL1Size          int
+	CacheL1TTL           time.Duration
+	CacheInvalidation    string
+	IdempotencyTTL       time.Duration
+	OutboxEnable         bool
+	OutboxPollInterval   time.Duration
+	OutboxBatchSize      int
+	OutboxStream         string
+	OutboxStreamMaxLen   int
+	OutboxRetention      time.Duration
+	TracingExporter      string
+	TracingOTLPEndpoint  string
+	TracingOTLPInsecure  bool
+	TracingFile          string
+	TracingSampleRatio   float64
+	AuthEnable           bool
+	AuthHS256Secret      string
+	AuthJWKSFile         string
+	AuthJWKSURL          string
+	AuthIssuer           string
+	AuthAudience         string
+	TLSEnable            bool
+	TLSCertFile          string
+	TLSKeyFile           string
+	TLSCAFile            string
+	TLSClientAuth        bool
+	TLSServerName        string
+	TLSReloadInterval    time.Duration
+	RateLimitEnable      bool
+	RateLimits           string
+	RateLimitDefault     string
+	StartupPolicy        string
+	StartupTimeout       time.Duration
+	HealthProbeInterval  time.Duration
+	ShutdownDrainDelay   time.Duration
+	ShutdownTimeout      time.Duration
 }
 
 func Load() (*Config, error) {
@@ -33,12 +79,57 @@ func Load() (*Config, error) {
 		EnableHTTPHandler:    mustGetBool("HTTP_HANDLER_ENABLE", false),
 		HTTPPort:             mustGetInt("HTTP_PORT", 8080), //nolint:mnd // false-positive
 		LogLevel:             getEnv("LOG_LEVEL", "info"),
+		LogFormat:            getEnv("LOG_FORMAT", "text"),
 		DBHost:               getEnv("POSTGRES_HOST", "localhost"),
 		DBPort:               mustGetInt("POSTGRES_PORT", 5432), //nolint:mnd // false-positive
 		DBUser:               getEnv("POSTGRES_USERNAME", "postgres"),
 		DBPassword:           getEnv("POSTGRES_PASSWORD", "postgres"),
 		DBName:               getEnv("POSTGRES_DATABASE", "postgres"),
 		RedisURI:             getEnv("REDIS_URI", "redis://localhost:6379"),
+		StorageBackend:       getEnv("STORAGE_BACKEND", "postgres"),
+		SQLitePath:           getEnv("SQLITE_PATH", "orders.db"),
+		CacheEnable:          mustGetBool("CACHE_ENABLE", true),
+		CacheTTL:             mustGetDuration("CACHE_TTL", 5*time.Minute), //nolint:mnd // false-positive
+		CacheStore:           getEnv("CACHE_STORE", "redis"),
+		CacheSize:            mustGetInt("CACHE_SIZE", 10000), //nolint:mnd // false-positive
+		CacheKeyPrefix:       getEnv("CACHE_KEY_PREFIX", "order:"),
+		CacheL1Enable:        mustGetBool("CACHE_L1_ENABLE", true),
+		CacheL1Size:          mustGetInt("CACHE_L1_SIZE", 1000),               //nolint:mnd // false-positive
+		CacheL1TTL:           mustGetDuration("CACHE_L1_TTL", 30*time.Second), //nolint:mnd // false-positive
+		CacheInvalidation:    getEnv("CACHE_INVALIDATION_CHANNEL", "orders.cache.invalidate"),
+		IdempotencyTTL:       mustGetPositiveDuration("IDEMPOTENCY_TTL", 24*time.Hour), //nolint:mnd // false-positive
+		OutboxEnable:         mustGetBool("OUTBOX_ENABLE", true),
+		OutboxPollInterval:   mustGetPositiveDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:      mustGetPositiveInt("OUTBOX_BATCH_SIZE", 100), //nolint:mnd // false-positive
+		OutboxStream:         getEnv("OUTBOX_STREAM", "orders.events"),
+		OutboxStreamMaxLen:   mustGetInt("OUTBOX_STREAM_MAXLEN", 100000),                 //nolint:mnd // false-positive
+		OutboxRetention:      mustGetPositiveDuration("OUTBOX_RETENTION", 168*time.Hour), //nolint:mnd // false-positive
+		TracingExporter:      getEnv("TRACING_EXPORTER", "none"),
+		TracingOTLPEndpoint:  getEnv("TRACING_OTLP_ENDPOINT", "localhost:4317"),
+		TracingOTLPInsecure:  mustGetBool("TRACING_OTLP_INSECURE", true),
+		TracingFile:          getEnv("TRACING_FILE", "traces.json"),
+		TracingSampleRatio:   mustGetFloat("TRACING_SAMPLE_RATIO", 1),
+		AuthEnable:           mustGetBool("AUTH_ENABLE", false),
+		AuthHS256Secret:      getEnv("AUTH_HS256_SECRET", ""),
+		AuthJWKSFile:         getEnv("AUTH_JWKS_FILE", ""),
+		AuthJWKSURL:          getEnv("AUTH_JWKS_URL", ""),
+		AuthIssuer:           getEnv("AUTH_ISSUER", ""),
+		AuthAudience:         getEnv("AUTH_AUDIENCE", ""),
+		TLSEnable:            mustGetBool("TLS_ENABLE", false),
+		TLSCertFile:          getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:           getEnv("TLS_KEY_FILE", ""),
+		TLSCAFile:            getEnv("TLS_CA_FILE", ""),
+		TLSClientAuth:        mustGetBool("TLS_CLIENT_AUTH", false),
+		TLSServerName:        getEnv("TLS_SERVER_NAME", "localhost"),
+		TLSReloadInterval:    mustGetDuration("TLS_RELOAD_INTERVAL", 10*time.Second), //nolint:mnd // false-positive
+		RateLimitEnable:      mustGetBool("RATE_LIMIT_ENABLE", false),
+		RateLimits:           getEnv("RATE_LIMITS", "CreateOrder=10/1s:20"),
+		RateLimitDefault:     getEnv("RATE_LIMIT_DEFAULT", ""),
+		StartupPolicy:        getEnv("STARTUP_POLICY", "degraded"),
+		StartupTimeout:       mustGetDuration("STARTUP_TIMEOUT", 2*time.Minute),               //nolint:mnd // false-positive
+		HealthProbeInterval:  mustGetPositiveDuration("HEALTH_PROBE_INTERVAL", 5*time.Second), //nolint:mnd // false-positive
+		ShutdownDrainDelay:   mustGetDuration("SHUTDOWN_DRAIN_DELAY", 0),
+		ShutdownTimeout:      mustGetDuration("SHUTDOWN_TIMEOUT", 30*time.Second), //nolint:mnd // false-positive
 	}, nil
 }
 
@@ -58,6 +149,14 @@ func mustGetInt(key string, def int) int {
 	return n
 }
 
+func mustGetPositiveInt(key string, def int) int {
+	n := mustGetInt(key, def)
+	if n <= 0 {
+		log.Fatalf("invalid int for %s: must be positive, got %d", key, n)
+	}
+	return n
+}
+
 func mustGetBool(key string, def bool) bool {
 	val := getEnv(key, strconv.FormatBool(def))
 	b, err := strconv.ParseBool(val)
@@ -67,6 +166,32 @@ func mustGetBool(key string, def bool) bool {
 	return b
 }
 
+func mustGetFloat(key string, def float64) float64 {
+	val := getEnv(key, strconv.FormatFloat(def, 'f', -1, 64))
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		log.Fatalf("invalid float for %s: %v", key, err)
+	}
+	return f
+}
+
+func mustGetDuration(key string, def time.Duration) time.Duration {
+	val := getEnv(key, def.String())
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Fatalf("invalid duration for %s: %v", key, err)
+	}
+	return d
+}
+
+func mustGetPositiveDuration(key string, def time.Duration) time.Duration {
+	d := mustGetDuration(key, def)
+	if d <= 0 {
+		log.Fatalf("invalid duration for %s: must be positive, got %s", key, d)
+	}
+	return d
+}
+
 func (c Config) BuildPostgresConnStr() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		c.DBHost, c.DBPort, c.DBUser, c.DBPassword, c.DBName)
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidResumeToken = errors.New("invalid resume token")
+	ErrResumeTokenExpired = errors.New("resume token expired")
+	ErrWatchUnavailable   = errors.New("order watching is not available")
+)
+
+type OrderEventType string
+
+const (
+	OrderEventCreated OrderEventType = "order.created"
+	OrderEventUpdated OrderEventType = "order.updated"
+	OrderEventDeleted OrderEventType = "order.deleted"
+)
+
+type OrderEvent struct {
+	ID   uuid.UUID
+	Type OrderEventType
+	// Order is the state after the change, or the last state for deletions.
+	Order      *Order
+	OccurredAt time.Time
+	// Sequence orders events of one source; zero until the event is stored.
+	Sequence int64
+}
+
+func NewOrderEvent(eventType OrderEventType, order *Order) OrderEvent {
+	return OrderEvent{
+		ID:         uuid.New(),
+		Type:       eventType,
+		Order:      order.Clone(),
+		OccurredAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+const MaxIdempotencyKeyLength = 255
+
+var (
+	ErrInvalidIdempotencyKey    = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+type IdempotencyRecord struct {
+	OrderID     uuid.UUID `json:"order_id"`
+	RequestHash string    `json:"request_hash"`
+	Completed   bool      `json:"completed"`
+}
+
+func NewIdempotencyRecord(order *Order) IdempotencyRecord {
+	data, _ := json.Marshal(order.Items)
+	sum := sha256.Sum256(data)
+
+	return IdempotencyRecord{
+		OrderID:     order.ID,
+		RequestHash: hex.EncodeToString(sum[:]),
+	}
+}
+
+func ValidateIdempotencyKey(key string) error {
+	if len(key) > MaxIdempotencyKeyLength {
+		return ErrInvalidIdempotencyKey
+	}
+
+	return nil
+}
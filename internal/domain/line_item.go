@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidLineItem = errors.New("invalid line item")
+)
+
+type LineItem struct {
+	Item     string `db:"item"     json:"item"     validate:"required,max=500"`
+	Quantity int32  `db:"quantity" json:"quantity" validate:"required,gt=0"`
+}
+
+func (li LineItem) Validate() error {
+	return validateStruct(li, ErrInvalidLineItem)
+}
@@ -3,8 +3,10 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
@@ -12,19 +14,35 @@ var (
 	ErrOrderAlreadyExist = errors.New("order already exist")
 	ErrOrderNotFound     = errors.New("order not found")
 	ErrInvalidOrderData  = errors.New("invalid order data")
+	ErrVersionMismatch   = errors.New("order version mismatch")
+	ErrInvalidETag       = errors.New("invalid etag")
 )
 
+// AnyVersion disables the optimistic concurrency check where a version is expected.
+const AnyVersion int64 = 0
+
+// AnyCustomer disables the ownership check where a customer id is expected.
+const AnyCustomer = ""
+
 type Order struct {
-	ID       uuid.UUID `db:"id"       json:"id"       validate:"required"`
-	Item     string    `db:"item"     json:"item"     validate:"required"`
-	Quantity int32     `db:"quantity" json:"quantity" validate:"required,gt=0"`
+	ID         uuid.UUID   `db:"id"          json:"id"          validate:"required"`
+	CustomerID string      `db:"customer_id" json:"customer_id" validate:"max=255"`
+	Status     OrderStatus `db:"status"      json:"status"      validate:"required"`
+	Items      []LineItem  `db:"-"           json:"items"       validate:"required,min=1"`
+	CreatedAt  time.Time   `db:"created_at"  json:"created_at"  validate:"required"`
+	Version    int64       `db:"version"     json:"version"     validate:"required,gt=0"`
 }
 
-func NewOrder(id uuid.UUID, item string, quantity int32) (*Order, error) {
+// NewOrder creates a pending order owned by customerID, which is empty for
+// orders placed without an authenticated caller.
+func NewOrder(id uuid.UUID, customerID string, items []LineItem) (*Order, error) {
 	order := &Order{
-		ID:       id,
-		Item:     item,
-		Quantity: quantity,
+		ID:         id,
+		CustomerID: customerID,
+		Status:     OrderStatusPending,
+		Items:      items,
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+		Version:    1,
 	}
 
 	err := order.Validate()
@@ -36,7 +54,87 @@ func NewOrder(id uuid.UUID, item string, quantity int32) (*Order, error) {
 }
 
 func (o *Order) Validate() error {
-	validate := validator.New()
+	if err := validateStruct(o, ErrInvalidOrderData); err != nil {
+		return err
+	}
+
+	verr := &ValidationError{Err: ErrInvalidOrderData}
+	if !o.Status.IsValid() {
+		verr.Violations = append(verr.Violations, FieldViolation{
+			Field:       "status",
+			Description: ErrInvalidOrderStatus.Error(),
+		})
+	}
+	for i, item := range o.Items {
+		err := item.Validate()
+		var itemErr *ValidationError
+		switch {
+		case err == nil:
+		case errors.As(err, &itemErr):
+			for _, v := range itemErr.Violations {
+				v.Field = fmt.Sprintf("items[%d].%s", i, v.Field)
+				verr.Violations = append(verr.Violations, v)
+			}
+		default:
+			return fmt.Errorf("%w: items[%d]: %w", ErrInvalidOrderData, i, err)
+		}
+	}
+	if len(verr.Violations) > 0 {
+		return verr
+	}
+
+	return nil
+}
+
+// Clone returns a copy of the order that shares no memory with it.
+func (o *Order) Clone() *Order {
+	clone := *o
+	clone.Items = slices.Clone(o.Items)
+
+	return &clone
+}
+
+func (o *Order) ETag() string {
+	return strconv.FormatInt(o.Version, 10)
+}
+
+// ParseETag returns AnyVersion for an empty etag.
+func ParseETag(etag string) (int64, error) {
+	if etag == "" {
+		return AnyVersion, nil
+	}
+
+	version, err := strconv.ParseInt(etag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, ErrInvalidETag
+	}
+
+	return version, nil
+}
+
+// OwnedBy reports whether customerID may access the order.
+func (o *Order) OwnedBy(customerID string) bool {
+	return customerID == AnyCustomer || o.CustomerID == customerID
+}
+
+func (o *Order) TotalQuantity() int64 {
+	var total int64
+	for _, item := range o.Items {
+		total += int64(item.Quantity)
+	}
+
+	return total
+}
+
+func (o *Order) Transition(to OrderStatus) error {
+	if !to.IsValid() {
+		return ErrInvalidOrderStatus
+	}
+	if !o.Status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, o.Status, to)
+	}
+
+	o.Status = to
 
-	return fmt.Errorf("%w: %w", ErrInvalidOrderData, validate.Struct(o))
+	return nil
 }
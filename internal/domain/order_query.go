@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 1000
+)
+
+var (
+	ErrInvalidPageToken  = errors.New("invalid page token")
+	ErrInvalidOrderQuery = errors.New("invalid order query")
+)
+
+type OrderSortField string
+
+const (
+	OrderSortByCreateTime OrderSortField = "create_time"
+	OrderSortByID         OrderSortField = "id"
+)
+
+type OrderSort struct {
+	Field OrderSortField
+	Desc  bool
+}
+
+type OrderFilter struct {
+	// CustomerID restricts results to one customer's orders unless it is
+	// AnyCustomer. It is set from the caller identity, not from requests.
+	CustomerID    string
+	Item          string
+	MinQuantity   *int32
+	MaxQuantity   *int32
+	Statuses      []OrderStatus
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+type OrderListQuery struct {
+	PageSize  int
+	PageToken string
+	Filter    OrderFilter
+	Sort      OrderSort
+}
+
+type OrderPage struct {
+	Orders        []*Order
+	NextPageToken string
+}
+
+func DefaultOrderSort() OrderSort {
+	return OrderSort{Field: OrderSortByCreateTime}
+}
+
+// ParseOrderSort parses an order_by expression such as "create_time desc".
+func ParseOrderSort(s string) (OrderSort, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		return DefaultOrderSort(), nil
+	}
+	if len(fields) > 2 { //nolint:mnd // field and direction
+		return OrderSort{}, NewFieldError(ErrInvalidOrderQuery, "order_by", fmt.Sprintf("%q has too many terms", s))
+	}
+
+	sort := OrderSort{Field: OrderSortField(fields[0])}
+	switch sort.Field {
+	case OrderSortByCreateTime, OrderSortByID:
+	default:
+		return OrderSort{}, NewFieldError(ErrInvalidOrderQuery, "order_by", fmt.Sprintf("unknown field %q", fields[0]))
+	}
+
+	if len(fields) == 2 { //nolint:mnd // field and direction
+		switch fields[1] {
+		case "asc":
+		case "desc":
+			sort.Desc = true
+		default:
+			return OrderSort{}, NewFieldError(ErrInvalidOrderQuery, "order_by",
+				fmt.Sprintf("unknown direction %q", fields[1]))
+		}
+	}
+
+	return sort, nil
+}
+
+func (s OrderSort) String() string {
+	if s.Desc {
+		return string(s.Field) + " desc"
+	}
+	return string(s.Field) + " asc"
+}
+
+// Less reports whether a sorts before b. Ties on the sort field are broken by ID.
+func (s OrderSort) Less(a, b *Order) bool {
+	cmp := 0
+	if s.Field == OrderSortByCreateTime {
+		cmp = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if cmp == 0 {
+		cmp = bytes.Compare(a.ID[:], b.ID[:])
+	}
+
+	if s.Desc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+func (q *OrderListQuery) Normalize() error {
+	switch {
+	case q.PageSize < 0:
+		return NewFieldError(ErrInvalidOrderQuery, "page_size", "must not be negative")
+	case q.PageSize == 0:
+		q.PageSize = DefaultPageSize
+	case q.PageSize > MaxPageSize:
+		q.PageSize = MaxPageSize
+	}
+
+	if q.Sort.Field == "" {
+		q.Sort.Field = OrderSortByCreateTime
+	}
+
+	return q.Filter.Validate()
+}
+
+func (f OrderFilter) Validate() error {
+	if f.MinQuantity != nil && f.MaxQuantity != nil && *f.MinQuantity > *f.MaxQuantity {
+		return NewFieldError(ErrInvalidOrderQuery, "filter.min_quantity", "must not be greater than max_quantity")
+	}
+	if !f.CreatedAfter.IsZero() && !f.CreatedBefore.IsZero() && !f.CreatedAfter.Before(f.CreatedBefore) {
+		return NewFieldError(ErrInvalidOrderQuery, "filter.created_after", "must be before created_before")
+	}
+	for _, status := range f.Statuses {
+		if !status.IsValid() {
+			return NewFieldError(ErrInvalidOrderQuery, "filter.statuses", ErrInvalidOrderStatus.Error())
+		}
+	}
+
+	return nil
+}
+
+func (f OrderFilter) Matches(o *Order) bool {
+	if !o.OwnedBy(f.CustomerID) {
+		return false
+	}
+
+	if f.Item != "" && !slices.ContainsFunc(o.Items, func(li LineItem) bool { return li.Item == f.Item }) {
+		return false
+	}
+
+	total := o.TotalQuantity()
+	if f.MinQuantity != nil && total < int64(*f.MinQuantity) {
+		return false
+	}
+	if f.MaxQuantity != nil && total > int64(*f.MaxQuantity) {
+		return false
+	}
+
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, o.Status) {
+		return false
+	}
+
+	if !f.CreatedAfter.IsZero() && o.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !o.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+
+	return true
+}
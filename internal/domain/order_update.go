@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrInvalidUpdateMask = errors.New("invalid update mask")
+)
+
+const (
+	OrderFieldItems         = "items"
+	OrderFieldItemsQuantity = "items.quantity"
+)
+
+type OrderPatch struct {
+	Items []LineItem
+}
+
+// UpdateMask is a validated set of order fields to update; build it with
+// ParseUpdateMask.
+type UpdateMask struct {
+	items          bool
+	itemQuantities bool
+}
+
+// ParseUpdateMask validates update mask paths. An empty mask selects every
+// updatable field.
+//
+// "items" replaces the whole list. "items.quantity" merges the patch items
+// by name instead: each sets the quantity of the line items with the same
+// name and is appended when the order has none, so one quantity can change
+// without resending the other items.
+func ParseUpdateMask(paths []string) (UpdateMask, error) {
+	if len(paths) == 0 {
+		return UpdateMask{items: true}, nil
+	}
+
+	var mask UpdateMask
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		switch path {
+		case OrderFieldItems:
+			mask.items = true
+		case OrderFieldItemsQuantity:
+			mask.itemQuantities = true
+		case "id", "status", "create_time", "etag", "customer_id":
+			return UpdateMask{}, updateMaskError("field %q is not updatable", path)
+		default:
+			return UpdateMask{}, updateMaskError("unknown field %q", path)
+		}
+
+		if seen[path] {
+			return UpdateMask{}, updateMaskError("duplicate field %q", path)
+		}
+		seen[path] = true
+	}
+
+	if mask.items && mask.itemQuantities {
+		return UpdateMask{}, updateMaskError("field %q conflicts with \"items\"", OrderFieldItemsQuantity)
+	}
+
+	return mask, nil
+}
+
+func updateMaskError(format, path string) *ValidationError {
+	return NewFieldError(ErrInvalidUpdateMask, "update_mask", fmt.Sprintf(format, path))
+}
+
+// Apply copies the fields selected by mask from patch.
+func (o *Order) Apply(patch OrderPatch, mask UpdateMask) error {
+	switch {
+	case mask.items:
+		o.Items = patch.Items
+	case mask.itemQuantities:
+		items, err := mergeItemQuantities(o.Items, patch.Items)
+		if err != nil {
+			return err
+		}
+		o.Items = items
+	}
+
+	return o.Validate()
+}
+
+func mergeItemQuantities(items, patch []LineItem) ([]LineItem, error) {
+	merged := slices.Clone(items)
+	seen := make(map[string]bool, len(patch))
+	for i, change := range patch {
+		if seen[change.Item] {
+			return nil, NewFieldError(ErrInvalidOrderData, fmt.Sprintf("items[%d].item", i),
+				fmt.Sprintf("duplicate item %q", change.Item))
+		}
+		seen[change.Item] = true
+
+		found := false
+		for j := range merged {
+			if merged[j].Item == change.Item {
+				merged[j].Quantity = change.Quantity
+				found = true
+			}
+		}
+		if !found {
+			merged = append(merged, change)
+		}
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldViolation describes a single invalid request field. Field is a path in
+// the API's field naming, e.g. "items[0].quantity".
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError lists the fields that failed validation and unwraps to the
+// sentinel error for the validated value, e.g. ErrInvalidOrderData.
+type ValidationError struct {
+	Err        error
+	Violations []FieldViolation
+}
+
+func NewFieldError(err error, field, description string) *ValidationError {
+	return &ValidationError{
+		Err:        err,
+		Violations: []FieldViolation{{Field: field, Description: description}},
+	}
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+" "+v.Description)
+	}
+
+	return e.Err.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	return validate
+}
+
+// validateStruct runs the struct tag validation on v and reports failures as
+// a ValidationError wrapping sentinel.
+func validateStruct(v any, sentinel error) error {
+	err := newValidator().Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return fmt.Errorf("%w: %w", sentinel, err)
+	}
+
+	verr := &ValidationError{Err: sentinel}
+	for _, fe := range fieldErrs {
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+		verr.Violations = append(verr.Violations, FieldViolation{
+			Field:       field,
+			Description: describeFieldError(fe),
+		})
+	}
+
+	return verr
+}
+
+func describeFieldError(fe validator.FieldError) string {
+	var unit string
+	switch fe.Kind() { //nolint:exhaustive // other kinds are compared by value
+	case reflect.Slice, reflect.Map:
+		unit = " element(s)"
+	case reflect.String:
+		unit = " character(s)"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must have at least " + fe.Param() + unit
+	case "max":
+		return "must have at most " + fe.Param() + unit
+	case "gt":
+		return "must be greater than " + fe.Param()
+	default:
+		return fmt.Sprintf("failed %q validation", fe.Tag())
+	}
+}
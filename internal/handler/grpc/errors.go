@@ -1,26 +1,104 @@
 package handler
 
 import (
+	"context"
 	"errors"
-	"log"
+	"log/slog"
 
 	"orderservice/internal/domain"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
 )
 
-func mapError(err error) error {
-	if errors.Is(err, domain.ErrOrderNotFound) {
-		return status.Error(codes.NotFound, err.Error())
+// ErrorDomain is reported in google.rpc.ErrorInfo for every domain error.
+const ErrorDomain = "orderservice"
+
+// errorMapping ties a domain error to its status code and a stable
+// ErrorInfo reason. field names the offending request field for errors that
+// do not carry their own field violations.
+type errorMapping struct {
+	err    error
+	code   codes.Code
+	reason string
+	field  string
+}
+
+// Reasons are part of the API contract: clients may switch on them, so they
+// must not change once published.
+//
+//nolint:gochecknoglobals // lookup table
+var errorMappings = []errorMapping{
+	{err: domain.ErrOrderNotFound, code: codes.NotFound, reason: "ORDER_NOT_FOUND"},
+	{err: domain.ErrOrderAlreadyExist, code: codes.AlreadyExists, reason: "ORDER_ALREADY_EXISTS"},
+	{err: domain.ErrVersionMismatch, code: codes.Aborted, reason: "VERSION_MISMATCH"},
+	{err: domain.ErrIdempotencyKeyInProgress, code: codes.Aborted, reason: "IDEMPOTENCY_KEY_IN_PROGRESS"},
+	{err: domain.ErrResumeTokenExpired, code: codes.OutOfRange, reason: "RESUME_TOKEN_EXPIRED"},
+	{err: domain.ErrWatchUnavailable, code: codes.Unimplemented, reason: "WATCH_UNAVAILABLE"},
+	{err: domain.ErrInvalidStatusTransition, code: codes.FailedPrecondition, reason: "INVALID_STATUS_TRANSITION"},
+	{err: domain.ErrInvalidOrderData, code: codes.InvalidArgument, reason: "INVALID_ORDER_DATA"},
+	{err: domain.ErrInvalidLineItem, code: codes.InvalidArgument, reason: "INVALID_LINE_ITEM", field: "items"},
+	{err: domain.ErrInvalidID, code: codes.InvalidArgument, reason: "INVALID_ID", field: "id"},
+	{err: domain.ErrInvalidOrderStatus, code: codes.InvalidArgument, reason: "INVALID_ORDER_STATUS", field: "status"},
+	{err: domain.ErrInvalidOrderQuery, code: codes.InvalidArgument, reason: "INVALID_ORDER_QUERY"},
+	{err: domain.ErrInvalidPageToken, code: codes.InvalidArgument, reason: "INVALID_PAGE_TOKEN", field: "page_token"},
+	{err: domain.ErrInvalidETag, code: codes.InvalidArgument, reason: "INVALID_ETAG", field: "etag"},
+	{err: domain.ErrInvalidUpdateMask, code: codes.InvalidArgument, reason: "INVALID_UPDATE_MASK", field: "update_mask"},
+	{
+		err: domain.ErrInvalidIdempotencyKey, code: codes.InvalidArgument,
+		reason: "INVALID_IDEMPOTENCY_KEY", field: "idempotency_key",
+	},
+	{
+		err: domain.ErrIdempotencyKeyReused, code: codes.InvalidArgument,
+		reason: "IDEMPOTENCY_KEY_REUSED", field: "idempotency_key",
+	},
+	{
+		err: domain.ErrInvalidResumeToken, code: codes.InvalidArgument,
+		reason: "INVALID_RESUME_TOKEN", field: "resume_token",
+	},
+}
+
+func mapError(ctx context.Context, err error) error {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			return m.status(ctx, err)
+		}
 	}
-	if errors.Is(err, domain.ErrOrderAlreadyExist) {
-		return status.Error(codes.AlreadyExists, err.Error())
+
+	slog.ErrorContext(ctx, "internal server error", slog.Any("error", err))
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func (m errorMapping) status(ctx context.Context, err error) error {
+	st := status.New(m.code, err.Error())
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: m.reason, Domain: ErrorDomain}}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	var verr *domain.ValidationError
+	if errors.As(err, &verr) {
+		for _, v := range verr.Violations {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+	} else if m.field != "" {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       m.field,
+			Description: err.Error(),
+		})
 	}
-	if errors.Is(err, domain.ErrInvalidOrderData) || errors.Is(err, domain.ErrInvalidID) {
-		return status.Error(codes.InvalidArgument, err.Error())
+	if len(violations) > 0 {
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
 	}
 
-	log.Printf("internal server error: %v", err)
-	return status.Error(codes.Internal, "internal server error")
+	withDetails, detailErr := st.WithDetails(details...)
+	if detailErr != nil {
+		slog.ErrorContext(ctx, "failed to attach error details", slog.Any("error", detailErr))
+		return st.Err()
+	}
+
+	return withDetails.Err()
 }
@@ -4,12 +4,18 @@ import (
 	"context"
 
 	"orderservice/internal/domain"
+	"orderservice/internal/protoconv"
 	"orderservice/internal/service"
 	pb "orderservice/pkg/api/order"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
 )
 
+// IdempotencyKeyMetadata is the gRPC metadata key carrying the CreateOrder
+// idempotency key when it is not set in the request body.
+const IdempotencyKeyMetadata = "idempotency-key"
+
 type OrderHandler struct {
 	pb.UnimplementedOrderServiceServer
 
@@ -22,21 +28,25 @@ func NewOrderHandler(service *service.OrderService) *OrderHandler {
 	}
 }
 
-func mapDomainStructToHandler(order *domain.Order) *pb.Order {
-	return &pb.Order{
-		Id:       order.ID.String(),
-		Item:     order.Item,
-		Quantity: order.Quantity,
+func idempotencyKey(ctx context.Context, req *pb.CreateOrderRequest) string {
+	if key := req.GetIdempotencyKey(); key != "" {
+		return key
+	}
+
+	if values := metadata.ValueFromIncomingContext(ctx, IdempotencyKeyMetadata); len(values) > 0 {
+		return values[0]
 	}
+
+	return ""
 }
 
 func (h *OrderHandler) CreateOrder(
 	ctx context.Context,
 	req *pb.CreateOrderRequest,
 ) (*pb.CreateOrderResponse, error) {
-	order, err := h.service.Create(ctx, req.GetItem(), req.GetQuantity())
+	order, err := h.service.Create(ctx, idempotencyKey(ctx, req), protoconv.LineItemsFromProto(req.GetItems()))
 	if err != nil {
-		return nil, mapError(err)
+		return nil, mapError(ctx, err)
 	}
 
 	return &pb.CreateOrderResponse{Id: order.ID.String()}, nil
@@ -45,15 +55,15 @@ func (h *OrderHandler) CreateOrder(
 func (h *OrderHandler) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.GetOrderResponse, error) {
 	parsedID, err := uuid.Parse(req.GetId())
 	if err != nil {
-		return nil, domain.ErrInvalidID
+		return nil, mapError(ctx, domain.ErrInvalidID)
 	}
 
 	order, err := h.service.Get(ctx, parsedID)
 	if err != nil {
-		return nil, mapError(err)
+		return nil, mapError(ctx, err)
 	}
 
-	return &pb.GetOrderResponse{Order: mapDomainStructToHandler(order)}, nil
+	return &pb.GetOrderResponse{Order: protoconv.OrderToProto(order)}, nil
 }
 
 func (h *OrderHandler) UpdateOrder(
@@ -62,15 +72,24 @@ func (h *OrderHandler) UpdateOrder(
 ) (*pb.UpdateOrderResponse, error) {
 	parsedID, err := uuid.Parse(req.GetId())
 	if err != nil {
-		return nil, mapError(domain.ErrInvalidID)
+		return nil, mapError(ctx, domain.ErrInvalidID)
+	}
+
+	version, err := domain.ParseETag(req.GetEtag())
+	if err != nil {
+		return nil, mapError(ctx, err)
 	}
 
-	order, err := h.service.Update(ctx, parsedID, req.GetItem(), req.GetQuantity())
+	patch := domain.OrderPatch{
+		Items: protoconv.LineItemsFromProto(req.GetItems()),
+	}
+
+	order, err := h.service.Update(ctx, parsedID, version, patch, req.GetUpdateMask().GetPaths())
 	if err != nil {
-		return nil, mapError(err)
+		return nil, mapError(ctx, err)
 	}
 
-	return &pb.UpdateOrderResponse{Order: mapDomainStructToHandler(order)}, nil
+	return &pb.UpdateOrderResponse{Order: protoconv.OrderToProto(order)}, nil
 }
 
 func (h *OrderHandler) DeleteOrder(
@@ -79,12 +98,17 @@ func (h *OrderHandler) DeleteOrder(
 ) (*pb.DeleteOrderResponse, error) {
 	parsedID, err := uuid.Parse(req.GetId())
 	if err != nil {
-		return nil, domain.ErrInvalidID
+		return nil, mapError(ctx, domain.ErrInvalidID)
 	}
 
-	err = h.service.Delete(ctx, parsedID)
+	version, err := domain.ParseETag(req.GetEtag())
 	if err != nil {
-		return nil, mapError(err)
+		return nil, mapError(ctx, err)
+	}
+
+	err = h.service.Delete(ctx, parsedID, version)
+	if err != nil {
+		return nil, mapError(ctx, err)
 	}
 
 	return &pb.DeleteOrderResponse{Success: true}, nil
@@ -92,17 +116,44 @@ func (h *OrderHandler) DeleteOrder(
 
 func (h *OrderHandler) ListOrders(
 	ctx context.Context,
-	_ *pb.ListOrdersRequest,
+	req *pb.ListOrdersRequest,
 ) (*pb.ListOrdersResponse, error) {
-	domainOrders, err := h.service.List(ctx)
+	query, err := protoconv.OrderListQueryFromProto(req)
+	if err != nil {
+		return nil, mapError(ctx, err)
+	}
+
+	page, err := h.service.List(ctx, query)
+	if err != nil {
+		return nil, mapError(ctx, err)
+	}
+
+	orders := make([]*pb.Order, 0, len(page.Orders))
+	for _, o := range page.Orders {
+		orders = append(orders, protoconv.OrderToProto(o))
+	}
+
+	return &pb.ListOrdersResponse{Orders: orders, NextPageToken: page.NextPageToken}, nil
+}
+
+func (h *OrderHandler) TransitionOrder(
+	ctx context.Context,
+	req *pb.TransitionOrderRequest,
+) (*pb.TransitionOrderResponse, error) {
+	parsedID, err := uuid.Parse(req.GetId())
 	if err != nil {
-		return nil, mapError(err)
+		return nil, mapError(ctx, domain.ErrInvalidID)
 	}
 
-	orders := make([]*pb.Order, 0, len(domainOrders))
-	for _, o := range domainOrders {
-		orders = append(orders, mapDomainStructToHandler(o))
+	status, err := protoconv.OrderStatusFromProto(req.GetStatus())
+	if err != nil {
+		return nil, mapError(ctx, err)
+	}
+
+	order, err := h.service.Transition(ctx, parsedID, status)
+	if err != nil {
+		return nil, mapError(ctx, err)
 	}
 
-	return &pb.ListOrdersResponse{Orders: orders}, nil
+	return &pb.TransitionOrderResponse{Order: protoconv.OrderToProto(order)}, nil
 }
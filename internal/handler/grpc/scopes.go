@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"orderservice/internal/auth"
+	pb "orderservice/pkg/api/order"
+)
+
+// MethodScopes maps every OrderService method to the scope a caller needs.
+func MethodScopes() map[string]string {
+	return map[string]string{
+		pb.OrderService_CreateOrder_FullMethodName:     auth.ScopeOrdersWrite,
+		pb.OrderService_GetOrder_FullMethodName:        auth.ScopeOrdersRead,
+		pb.OrderService_UpdateOrder_FullMethodName:     auth.ScopeOrdersWrite,
+		pb.OrderService_DeleteOrder_FullMethodName:     auth.ScopeOrdersWrite,
+		pb.OrderService_ListOrders_FullMethodName:      auth.ScopeOrdersRead,
+		pb.OrderService_TransitionOrder_FullMethodName: auth.ScopeOrdersWrite,
+		pb.OrderService_WatchOrders_FullMethodName:     auth.ScopeOrdersRead,
+	}
+}
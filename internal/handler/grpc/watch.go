@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"orderservice/internal/domain"
+	"orderservice/internal/protoconv"
+	pb "orderservice/pkg/api/order"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// LastEventIDMetadata carries the SSE Last-Event-ID header forwarded by the
+// gateway, used as the resume token when the request does not set one.
+const LastEventIDMetadata = "last-event-id"
+
+func encodeResumeToken(sequence int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(sequence, 10)))
+}
+
+func parseResumeToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, domain.ErrInvalidResumeToken
+	}
+
+	sequence, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil || sequence <= 0 {
+		return 0, domain.ErrInvalidResumeToken
+	}
+
+	return sequence, nil
+}
+
+func (h *OrderHandler) WatchOrders(
+	req *pb.WatchOrdersRequest,
+	stream grpc.ServerStreamingServer[pb.WatchOrdersResponse],
+) error {
+	ctx := stream.Context()
+
+	filter, err := protoconv.OrderFilterFromProto(req.GetFilter())
+	if err != nil {
+		return mapError(ctx, err)
+	}
+
+	token := req.GetResumeToken()
+	if values := metadata.ValueFromIncomingContext(ctx, LastEventIDMetadata); token == "" && len(values) > 0 {
+		token = values[0]
+	}
+
+	after, err := parseResumeToken(token)
+	if err != nil {
+		return mapError(ctx, err)
+	}
+
+	err = h.service.Watch(ctx, filter, after, func(event domain.OrderEvent) error {
+		return stream.Send(&pb.WatchOrdersResponse{
+			Event:       protoconv.OrderEventToProto(event),
+			ResumeToken: encodeResumeToken(event.Sequence),
+		})
+	})
+	if err != nil && ctx.Err() == nil {
+		return mapError(ctx, err)
+	}
+
+	return nil
+}
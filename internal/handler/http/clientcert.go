@@ -0,0 +1,29 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	rpccode "google.golang.org/genproto/googleapis/rpc/code"
+)
+
+// RequireClientCertMiddleware rejects requests whose connection carries no
+// verified client certificate. It guards the API routes when the listener
+// only verifies certificates that are offered, keeping probes and metrics
+// reachable without one.
+func RequireClientCertMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{Error: ErrorBody{
+				Code:    http.StatusUnauthorized,
+				Status:  rpccode.Code_UNAUTHENTICATED.String(),
+				Message: "client certificate required",
+			}})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
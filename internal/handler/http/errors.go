@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	rpccode "google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrorResponse is the gateway's JSON error body. Unlike the default
+// google.rpc.Status rendering it flattens the ErrorInfo and BadRequest
+// details so clients don't have to decode Any messages.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code            int               `json:"code"`
+	Status          string            `json:"status"`
+	Message         string            `json:"message"`
+	Reason          string            `json:"reason,omitempty"`
+	Domain          string            `json:"domain,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	FieldViolations []FieldViolation  `json:"field_violations,omitempty"`
+}
+
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ErrorHandler sets Retry-After from a RetryInfo detail and then defers to
+// the default handler, which renders the body through RewriteErrorResponse.
+func ErrorHandler(
+	ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler,
+	w http.ResponseWriter, r *http.Request, err error,
+) {
+	for _, detail := range grpcstatus.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			seconds := math.Ceil(info.GetRetryDelay().AsDuration().Seconds())
+			w.Header().Set("Retry-After", strconv.Itoa(int(seconds)))
+		}
+	}
+
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
+
+// RewriteErrorResponse replaces google.rpc.Status bodies with ErrorResponse
+// and passes every other response through unchanged.
+func RewriteErrorResponse(_ context.Context, response proto.Message) (any, error) {
+	st, ok := response.(*status.Status)
+	if !ok {
+		return response, nil
+	}
+
+	code := codes.Code(st.GetCode()) //nolint:gosec // status codes fit in uint32
+	body := ErrorBody{
+		Code:    runtime.HTTPStatusFromCode(code),
+		Status:  rpccode.Code(st.GetCode()).String(),
+		Message: st.GetMessage(),
+	}
+	for _, detail := range grpcstatus.FromProto(st).Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			body.Reason = d.GetReason()
+			body.Domain = d.GetDomain()
+			body.Metadata = d.GetMetadata()
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				body.FieldViolations = append(body.FieldViolations, FieldViolation{
+					Field:       v.GetField(),
+					Description: v.GetDescription(),
+				})
+			}
+		}
+	}
+
+	return ErrorResponse{Error: body}, nil
+}
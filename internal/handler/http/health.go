@@ -10,15 +10,22 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// HealthHandler probes the dependencies in use: a nil DB is skipped, and a
+// nil Redis is only reported when RedisInUse says the server wanted one.
+// DBName labels the database in the response, e.g. "postgres".
 type HealthHandler struct {
-	DB    *sqlx.DB
-	Redis *redis.Client
+	DBName     string
+	DB         *sqlx.DB
+	Redis      *redis.Client
+	RedisInUse bool
 }
 
-func NewHealthHandler(db *sqlx.DB, redisDB *redis.Client) *HealthHandler {
+func NewHealthHandler(dbName string, db *sqlx.DB, redisDB *redis.Client, redisInUse bool) *HealthHandler {
 	return &HealthHandler{
-		DB:    db,
-		Redis: redisDB,
+		DBName:     dbName,
+		DB:         db,
+		Redis:      redisDB,
+		RedisInUse: redisInUse,
 	}
 }
 
@@ -33,21 +40,32 @@ func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	details := map[string]string{}
 
-	if err := h.DB.PingContext(ctx); err != nil {
-		details["postgres"] = "unhealthy: " + err.Error()
-	} else {
-		details["postgres"] = "ok"
+	if h.DB != nil {
+		if err := h.DB.PingContext(ctx); err != nil {
+			details[h.DBName] = "unhealthy: " + err.Error()
+		} else {
+			details[h.DBName] = "ok"
+		}
 	}
 
-	if err := h.Redis.Ping(ctx).Err(); err != nil {
-		details["redis"] = "unhealthy: " + err.Error()
-	} else {
-		details["redis"] = "ok"
+	status := "ok"
+
+	// A nil Redis client the server wanted means it started in degraded mode:
+	// it is serving without cache, so report it without failing the check.
+	switch {
+	case h.Redis != nil:
+		if err := h.Redis.Ping(ctx).Err(); err != nil {
+			details["redis"] = "unhealthy: " + err.Error()
+		} else {
+			details["redis"] = "ok"
+		}
+	case h.RedisInUse:
+		details["redis"] = "disabled"
+		status = "degraded"
 	}
 
-	status := "ok"
 	for _, v := range details {
-		if v != "ok" {
+		if v != "ok" && v != "disabled" {
 			status = "unhealthy"
 			break
 		}
@@ -56,8 +74,52 @@ func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	resp := HealthResponse{Status: status, Details: details}
 
 	w.Header().Set("Content-Type", "application/json")
-	if status != "ok" {
+	if status == "unhealthy" {
 		w.WriteHeader(http.StatusServiceUnavailable)
 	}
 	_ = json.NewEncoder(w).Encode(resp)
 }
+
+type Readiness interface {
+	Ready() (bool, map[string]string)
+}
+
+// ReadinessHandler answers /readyz from the latest dependency probes, so it
+// fails while dependencies are down and once shutdown has begun.
+type ReadinessHandler struct {
+	readiness Readiness
+}
+
+func NewReadinessHandler(readiness Readiness) *ReadinessHandler {
+	return &ReadinessHandler{
+		readiness: readiness,
+	}
+}
+
+func (h *ReadinessHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	ready, details := h.readiness.Ready()
+
+	resp := HealthResponse{Status: "ok", Details: details}
+	if !ready {
+		resp.Status = "unavailable"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// LivenessHandler answers /livez. It checks no dependency: a process that
+// can serve HTTP is alive, and restarting it would not fix a database outage.
+type LivenessHandler struct{}
+
+func NewLivenessHandler() *LivenessHandler {
+	return &LivenessHandler{}
+}
+
+func (h *LivenessHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(HealthResponse{Status: "ok", Details: map[string]string{}})
+}
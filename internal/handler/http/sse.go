@@ -0,0 +1,73 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "orderservice/pkg/api/order"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/proto"
+)
+
+const EventStreamContentType = "text/event-stream"
+
+// SSEMarshaler renders gateway server-streaming responses as server-sent
+// events. WatchOrders resume tokens become event ids, so browsers resume
+// through the Last-Event-ID header automatically.
+type SSEMarshaler struct {
+	runtime.JSONPb
+}
+
+func NewSSEMarshaler() *SSEMarshaler {
+	return &SSEMarshaler{}
+}
+
+func (m *SSEMarshaler) ContentType(_ any) string {
+	return EventStreamContentType
+}
+
+func (m *SSEMarshaler) Delimiter() []byte {
+	return []byte("\n\n")
+}
+
+func (m *SSEMarshaler) Marshal(v any) ([]byte, error) {
+	data, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// The gateway wraps stream messages in map[string]any{"result": ...} and
+	// stream errors in map[string]proto.Message{"error": ...}.
+	var b bytes.Buffer
+	switch chunk := v.(type) {
+	case map[string]any:
+		if resp, ok := chunk["result"].(*pb.WatchOrdersResponse); ok && resp.GetResumeToken() != "" {
+			b.WriteString("id: " + resp.GetResumeToken() + "\n")
+		}
+		if _, ok := chunk["error"]; ok {
+			b.WriteString("event: error\n")
+		}
+	case map[string]proto.Message:
+		if _, ok := chunk["error"]; ok {
+			b.WriteString("event: error\n")
+		}
+	}
+	b.WriteString("data: ")
+	b.Write(data)
+
+	return b.Bytes(), nil
+}
+
+// StreamingDeadlineMiddleware lifts the server write timeout for event
+// streams, which stay open for as long as the client is connected.
+func StreamingDeadlineMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Accept"), EventStreamContentType) {
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,45 @@
+package http_test
+
+import (
+	"strings"
+	"testing"
+
+	httpHandlers "orderservice/internal/handler/http"
+	pb "orderservice/pkg/api/order"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSSEMarshalerMarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		chunk any
+		want  string
+	}{
+		{
+			name:  "result with resume token",
+			chunk: map[string]any{"result": &pb.WatchOrdersResponse{ResumeToken: "42"}},
+			want:  "id: 42\ndata: ",
+		},
+		{
+			// Stream errors are built by the gateway's errorChunk.
+			name:  "error chunk",
+			chunk: map[string]proto.Message{"error": status.New(codes.Unavailable, "gone").Proto()},
+			want:  "event: error\ndata: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := httpHandlers.NewSSEMarshaler().Marshal(tt.chunk)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if !strings.HasPrefix(string(got), tt.want) {
+				t.Errorf("Marshal = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const probeTimeout = 2 * time.Second
+
+// Probe reports whether a dependency is usable.
+type Probe func(ctx context.Context) error
+
+// Checker periodically runs dependency probes and publishes the result
+// through the grpc.health.v1 server for the overall server ("") and every
+// registered service. It is not ready until the first round of probes.
+type Checker struct {
+	server   *health.Server
+	services []string
+	interval time.Duration
+
+	mu           sync.RWMutex
+	probes       map[string]Probe
+	results      map[string]error
+	checked      bool
+	shuttingDown bool
+}
+
+func NewChecker(server *health.Server, services []string, interval time.Duration) *Checker {
+	c := &Checker{
+		server:   server,
+		services: append([]string{""}, services...),
+		interval: interval,
+		probes:   make(map[string]Probe),
+		results:  make(map[string]error),
+	}
+	c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return c
+}
+
+func (c *Checker) AddProbe(name string, probe Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.probes[name] = probe
+}
+
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Checker) check(ctx context.Context) {
+	c.mu.RLock()
+	probes := make(map[string]Probe, len(c.probes))
+	for name, probe := range c.probes {
+		probes[name] = probe
+	}
+	c.mu.RUnlock()
+
+	results := make(map[string]error, len(probes))
+	for name, probe := range probes {
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		results[name] = probe(probeCtx)
+		cancel()
+
+		if results[name] != nil {
+			slog.WarnContext(ctx, "health probe failed", slog.String("dependency", name), slog.Any("error", results[name]))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.shuttingDown {
+		return
+	}
+
+	c.results = results
+	c.checked = true
+
+	status := healthpb.HealthCheckResponse_SERVING
+	for _, err := range results {
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			break
+		}
+	}
+	c.setStatus(status)
+}
+
+// Ready reports whether the server should receive traffic, along with the
+// state of every probed dependency.
+func (c *Checker) Ready() (bool, map[string]string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ready := c.checked && !c.shuttingDown
+	details := make(map[string]string, len(c.results))
+	for name, err := range c.results {
+		if err != nil {
+			details[name] = "unhealthy: " + err.Error()
+			ready = false
+		} else {
+			details[name] = "ok"
+		}
+	}
+	if c.shuttingDown {
+		details["server"] = "shutting down"
+	}
+
+	return ready, details
+}
+
+// Shutdown reports NOT_SERVING for good, so that load balancers stop sending
+// new calls while in-flight ones drain.
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.shuttingDown = true
+	c.server.Shutdown()
+}
+
+func (c *Checker) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, status)
+	}
+}
@@ -2,17 +2,30 @@ package interceptor
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
+	"orderservice/internal/logger"
+
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
-type LoggerInterceptor struct{}
+// RequestIDMetadata is the metadata key used to accept and echo request IDs.
+const RequestIDMetadata = "x-request-id"
+
+type LoggerInterceptor struct {
+	logger *slog.Logger
+}
 
-func NewLoggerInterceptor() *LoggerInterceptor {
-	return &LoggerInterceptor{}
+func NewLoggerInterceptor(logger *slog.Logger) *LoggerInterceptor {
+	return &LoggerInterceptor{
+		logger: logger,
+	}
 }
 
 func (i *LoggerInterceptor) Unary() grpc.UnaryServerInterceptor {
@@ -24,22 +37,17 @@ func (i *LoggerInterceptor) Unary() grpc.UnaryServerInterceptor {
 	) (any, error) {
 		start := time.Now()
 
-		log.Printf("gRPC method %s called", info.FullMethod)
+		ctx = withRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, logger.RequestID(ctx)))
 
-		resp, err := handler(ctx, req)
+		i.logger.DebugContext(ctx, "gRPC call started",
+			slog.String("method", info.FullMethod),
+			slog.String("peer", peerAddr(ctx)),
+		)
 
-		duration := time.Since(start)
+		resp, err := handler(ctx, req)
 
-		if err != nil {
-			if st, ok := status.FromError(err); ok {
-				log.Printf("error: %s, code: %s, duration: %v",
-					st.Message(), st.Code(), duration)
-			} else {
-				log.Printf("error: %v, duration: %v", err, duration)
-			}
-		} else {
-			log.Printf("method %s completed in %v", info.FullMethod, duration)
-		}
+		i.logResult(ctx, "gRPC call finished", info.FullMethod, err, time.Since(start))
 
 		return resp, err
 	}
@@ -54,20 +62,84 @@ func (i *LoggerInterceptor) Stream() grpc.StreamServerInterceptor {
 	) error {
 		start := time.Now()
 
-		log.Printf("gRPC stream method %s started", info.FullMethod)
+		ctx := withRequestID(stream.Context())
+		_ = stream.SetHeader(metadata.Pairs(RequestIDMetadata, logger.RequestID(ctx)))
 
-		err := handler(srv, stream)
+		i.logger.DebugContext(ctx, "gRPC stream started",
+			slog.String("method", info.FullMethod),
+			slog.String("peer", peerAddr(ctx)),
+		)
 
-		duration := time.Since(start)
+		err := handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
 
-		if err != nil {
-			log.Printf("stream method %s failed: %v, duration: %v",
-				info.FullMethod, err, duration)
-		} else {
-			log.Printf("stream method %s completed in %v",
-				info.FullMethod, duration)
-		}
+		i.logResult(ctx, "gRPC stream finished", info.FullMethod, err, time.Since(start))
 
 		return err
 	}
 }
+
+func (i *LoggerInterceptor) logResult(ctx context.Context, msg, method string, err error, duration time.Duration) {
+	code := status.Code(err)
+
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", duration),
+		slog.String("peer", peerAddr(ctx)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+
+	i.logger.LogAttrs(ctx, levelForCode(code), msg, attrs...)
+}
+
+func levelForCode(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK, codes.Canceled:
+		return slog.LevelInfo
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange,
+		codes.ResourceExhausted, codes.Unimplemented, codes.DeadlineExceeded:
+		return slog.LevelWarn
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return slog.LevelError
+	default:
+		return slog.LevelError
+	}
+}
+
+// withRequestID keeps a request ID already assigned by an outer interceptor,
+// then reuses the caller's request ID or generates a new one.
+func withRequestID(ctx context.Context) context.Context {
+	if logger.RequestID(ctx) != "" {
+		return ctx
+	}
+
+	requestID := ""
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDMetadata); len(values) > 0 {
+		requestID = values[0]
+	}
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+
+	return logger.WithRequestID(ctx, requestID)
+}
+
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+type contextStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint:containedctx // overrides the stream context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,55 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"orderservice/internal/metrics"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+type MetricsInterceptor struct {
+	metrics *metrics.Metrics
+}
+
+func NewMetricsInterceptor(metrics *metrics.Metrics) *MetricsInterceptor {
+	return &MetricsInterceptor{
+		metrics: metrics,
+	}
+}
+
+func (i *MetricsInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		i.metrics.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+		return resp, err
+	}
+}
+
+func (i *MetricsInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+
+		err := handler(srv, stream)
+
+		i.metrics.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+		return err
+	}
+}
@@ -0,0 +1,134 @@
+package interceptor
+
+import (
+	"context"
+	"math"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+
+	"orderservice/internal/auth"
+	"orderservice/internal/ratelimit"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	RetryAfterMetadata       = "retry-after"
+	forwardedForMetadata     = "x-forwarded-for"
+	rateLimitExceededMessage = "rate limit exceeded"
+)
+
+type RateLimitInterceptor struct {
+	limiter      *ratelimit.Limiter
+	limits       map[string]ratelimit.Limit
+	defaultLimit *ratelimit.Limit
+}
+
+// NewRateLimitInterceptor limits every caller per method. limits is keyed by
+// full method name or bare method name; methods without an entry use
+// defaultLimit, or are not limited when it is nil.
+func NewRateLimitInterceptor(
+	limiter *ratelimit.Limiter,
+	limits map[string]ratelimit.Limit,
+	defaultLimit *ratelimit.Limit,
+) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		limiter:      limiter,
+		limits:       limits,
+		defaultLimit: defaultLimit,
+	}
+}
+
+func (i *RateLimitInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if err := i.take(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (i *RateLimitInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := i.take(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+func (i *RateLimitInterceptor) take(ctx context.Context, method string) error {
+	limit, ok := i.limits[method]
+	if !ok {
+		limit, ok = i.limits[path.Base(method)]
+	}
+	if !ok {
+		if i.defaultLimit == nil {
+			return nil
+		}
+		limit = *i.defaultLimit
+	}
+
+	result := i.limiter.Take(ctx, method+":"+clientKey(ctx), limit)
+	if result.Allowed {
+		return nil
+	}
+
+	retryAfter := max(1, int(math.Ceil(result.RetryAfter.Seconds())))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterMetadata, strconv.Itoa(retryAfter)))
+
+	st, err := status.New(codes.ResourceExhausted, rateLimitExceededMessage).WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(result.RetryAfter)},
+	)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, rateLimitExceededMessage)
+	}
+
+	return st.Err()
+}
+
+// clientKey identifies the caller by principal, or by address for anonymous
+// calls. The gateway dials over loopback, so for loopback peers the client
+// address the gateway appended to x-forwarded-for is used instead.
+func clientKey(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		return "sub:" + principal.Subject
+	}
+
+	var host string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host = p.Addr.String()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if values := metadata.ValueFromIncomingContext(ctx, forwardedForMetadata); len(values) > 0 {
+			hops := strings.Split(values[len(values)-1], ",")
+			host = strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+
+	return "ip:" + host
+}
@@ -0,0 +1,80 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+
+	"orderservice/internal/metrics"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type RecoveryInterceptor struct {
+	logger  *slog.Logger
+	metrics *metrics.Metrics
+}
+
+func NewRecoveryInterceptor(logger *slog.Logger, metrics *metrics.Metrics) *RecoveryInterceptor {
+	return &RecoveryInterceptor{
+		logger:  logger,
+		metrics: metrics,
+	}
+}
+
+func (i *RecoveryInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp any, err error) {
+		ctx = withRequestID(ctx)
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(ctx, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+func (i *RecoveryInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		ctx := withRequestID(stream.Context())
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(ctx, info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func (i *RecoveryInterceptor) recovered(ctx context.Context, method string, r any) error {
+	i.metrics.CountPanic(method)
+
+	i.logger.ErrorContext(ctx, "gRPC handler panicked",
+		slog.String("method", method),
+		slog.String("peer", peerAddr(ctx)),
+		slog.String("user_agent", strings.Join(metadata.ValueFromIncomingContext(ctx, "user-agent"), " ")),
+		slog.String("panic", fmt.Sprint(r)),
+		slog.String("stack", string(debug.Stack())),
+	)
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "orderservice"
+
+// Metrics holds every collector of the service. A nil *Metrics is valid and
+// records nothing, so instrumented components work without metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	grpcRequests    *prometheus.CounterVec
+	grpcDuration    *prometheus.HistogramVec
+	dbQueryDuration *prometheus.HistogramVec
+	cacheRequests   *prometheus.CounterVec
+	cacheTierGets   *prometheus.CounterVec
+	panics          *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Number of gRPC calls handled, by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of gRPC calls, by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Latency of repository database operations, by operation and outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "outcome"}),
+		cacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "requests_total",
+			Help:      "Number of cache operations, by operation and result (hit, miss, ok, error).",
+		}, []string{"operation", "result"}),
+		cacheTierGets: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "tier_gets_total",
+			Help:      "Number of lookups in each tier of a tiered cache, by tier (l1, l2) and result (hit, miss, error).",
+		}, []string{"tier", "result"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "panics_total",
+			Help:      "Number of panics recovered in gRPC handlers, by method.",
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.grpcRequests,
+		m.grpcDuration,
+		m.dbQueryDuration,
+		m.cacheRequests,
+		m.cacheTierGets,
+		m.panics,
+	)
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) ObserveGRPC(method, code string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveDBQuery(operation, outcome string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.dbQueryDuration.WithLabelValues(operation, outcome).Observe(duration.Seconds())
+}
+
+func (m *Metrics) CountCache(operation, result string) {
+	if m == nil {
+		return
+	}
+
+	m.cacheRequests.WithLabelValues(operation, result).Inc()
+}
+
+func (m *Metrics) CountCacheTier(tier, result string) {
+	if m == nil {
+		return
+	}
+
+	m.cacheTierGets.WithLabelValues(tier, result).Inc()
+}
+
+func (m *Metrics) CountPanic(method string) {
+	if m == nil {
+		return
+	}
+
+	m.panics.WithLabelValues(method).Inc()
+}
@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"orderservice/internal/domain"
+	"orderservice/internal/protoconv"
+	pb "orderservice/pkg/api/order"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"google.golang.org/protobuf/proto"
+)
+
+type Message struct {
+	ID          int64                 `db:"id"`
+	EventID     uuid.UUID             `db:"event_id"`
+	AggregateID uuid.UUID             `db:"aggregate_id"`
+	EventType   domain.OrderEventType `db:"event_type"`
+	Payload     []byte                `db:"payload"`
+	CreatedAt   time.Time             `db:"created_at"`
+}
+
+// NewMessage encodes event as a protobuf order.OrderEvent.
+func NewMessage(event domain.OrderEvent) (*Message, error) {
+	payload, err := proto.Marshal(protoconv.OrderEventToProto(event))
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s event: %w", event.Type, err)
+	}
+
+	return &Message{
+		EventID:     event.ID,
+		AggregateID: event.Order.ID,
+		EventType:   event.Type,
+		Payload:     payload,
+		CreatedAt:   event.OccurredAt,
+	}, nil
+}
+
+// Enqueue stores msg in the outbox as part of tx, so it is committed or
+// rolled back together with the change it describes.
+func Enqueue(ctx context.Context, tx *sqlx.Tx, msg *Message) error {
+	query := `
+		insert into order_outbox (event_id, aggregate_id, event_type, payload, created_at)
+		values (:event_id, :aggregate_id, :event_type, :payload, :created_at)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, msg); err != nil {
+		return fmt.Errorf("enqueue outbox message: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Message) Event() (domain.OrderEvent, error) {
+	var msg pb.OrderEvent
+	if err := proto.Unmarshal(m.Payload, &msg); err != nil {
+		return domain.OrderEvent{}, fmt.Errorf("unmarshal %s event: %w", m.EventType, err)
+	}
+
+	event, err := protoconv.OrderEventFromProto(&msg)
+	if err != nil {
+		return domain.OrderEvent{}, fmt.Errorf("decode %s event: %w", m.EventType, err)
+	}
+	event.Sequence = m.ID
+
+	return event, nil
+}
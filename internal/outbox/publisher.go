@@ -0,0 +1,45 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, msg *Message) error
+}
+
+type RedisStreamPublisher struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+func NewRedisStreamPublisher(client *redis.Client, stream string, maxLen int64) *RedisStreamPublisher {
+	return &RedisStreamPublisher{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, msg *Message) error {
+	err := p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: p.maxLen > 0,
+		Values: map[string]any{
+			"event_id":     msg.EventID.String(),
+			"event_type":   string(msg.EventType),
+			"aggregate_id": msg.AggregateID.String(),
+			"payload":      msg.Payload,
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("publish %s event %s: %w", msg.EventType, msg.EventID, err)
+	}
+
+	return nil
+}
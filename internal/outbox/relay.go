@@ -0,0 +1,175 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	DefaultRetention = 7 * 24 * time.Hour
+
+	pruneInterval  = time.Minute
+	pruneBatchSize = 1000
+)
+
+type Relay struct {
+	db        *sqlx.DB
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+	retention time.Duration
+}
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// Retention is how long dispatched messages are kept, so that order
+	// watchers can resume from them, and defaults to DefaultRetention.
+	Retention time.Duration
+}
+
+func NewRelay(db *sqlx.DB, publisher Publisher, config *Config) *Relay {
+	if config == nil {
+		config = &Config{
+			PollInterval: time.Second,
+			BatchSize:    100, //nolint:mnd // false-positive
+		}
+	}
+
+	relay := &Relay{
+		db:        db,
+		publisher: publisher,
+		interval:  config.PollInterval,
+		batchSize: config.BatchSize,
+		retention: config.Retention,
+	}
+	if relay.retention <= 0 {
+		relay.retention = DefaultRetention
+	}
+
+	return relay
+}
+
+// Run publishes pending outbox messages until ctx is cancelled. Full batches
+// are followed immediately by the next one; otherwise the relay waits for
+// the poll interval. Messages dispatched longer ago than the retention
+// period are deleted about once a minute.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+	for {
+		if time.Since(lastPrune) >= pruneInterval {
+			lastPrune = time.Now()
+			if err := r.prune(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.WarnContext(ctx, "outbox prune failed", slog.Any("error", err))
+			}
+		}
+
+		dispatched, err := r.dispatchBatch(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			slog.WarnContext(ctx, "outbox relay failed", slog.Any("error", err))
+		}
+
+		if err == nil && dispatched == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchBatch publishes the oldest pending messages in id order and marks
+// the published ones as dispatched. Rows are locked with skip locked so
+// several replicas can relay concurrently without publishing the same
+// message twice; a crash between publish and commit re-publishes it later.
+func (r *Relay) dispatchBatch(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const selectQuery = `
+		select id, event_id, aggregate_id, event_type, payload, created_at
+		from order_outbox
+		where dispatched_at is null
+		order by id
+		limit $1
+		for update skip locked
+	`
+
+	var messages []*Message
+	if err := tx.SelectContext(ctx, &messages, selectQuery, r.batchSize); err != nil {
+		return 0, fmt.Errorf("select outbox messages: %w", err)
+	}
+
+	dispatched := make([]int64, 0, len(messages))
+	var publishErr error
+	for _, msg := range messages {
+		if publishErr = r.publisher.Publish(ctx, msg); publishErr != nil {
+			break
+		}
+		dispatched = append(dispatched, msg.ID)
+	}
+
+	if len(dispatched) > 0 {
+		const updateQuery = `
+			update order_outbox
+			set dispatched_at = now()
+			where id = any($1)
+		`
+
+		if _, err := tx.ExecContext(ctx, updateQuery, pq.Array(dispatched)); err != nil {
+			return 0, fmt.Errorf("mark outbox messages dispatched: %w", err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("commit transaction: %w", err)
+		}
+	}
+
+	return len(dispatched), publishErr
+}
+
+// prune deletes messages dispatched before the retention period in batches,
+// so that a large backlog doesn't hold locks for long.
+func (r *Relay) prune(ctx context.Context) error {
+	const query = `
+		delete from order_outbox
+		where id in (
+			select id
+			from order_outbox
+			where dispatched_at < $1
+			limit $2
+			for update skip locked
+		)
+	`
+
+	cutoff := time.Now().Add(-r.retention)
+	for {
+		result, err := r.db.ExecContext(ctx, query, cutoff, pruneBatchSize)
+		if err != nil {
+			return fmt.Errorf("delete dispatched outbox messages: %w", err)
+		}
+
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("count deleted outbox messages: %w", err)
+		}
+		if deleted < pruneBatchSize {
+			return nil
+		}
+	}
+}
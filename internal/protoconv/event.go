@@ -0,0 +1,61 @@
+package protoconv
+
+import (
+	"fmt"
+
+	"orderservice/internal/domain"
+	pb "orderservice/pkg/api/order"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func OrderEventToProto(event domain.OrderEvent) *pb.OrderEvent {
+	msg := &pb.OrderEvent{
+		EventId:   event.ID.String(),
+		OccurTime: timestamppb.New(event.OccurredAt),
+	}
+
+	order := OrderToProto(event.Order)
+	switch event.Type {
+	case domain.OrderEventCreated:
+		msg.Payload = &pb.OrderEvent_Created{Created: &pb.OrderCreated{Order: order}}
+	case domain.OrderEventUpdated:
+		msg.Payload = &pb.OrderEvent_Updated{Updated: &pb.OrderUpdated{Order: order}}
+	case domain.OrderEventDeleted:
+		msg.Payload = &pb.OrderEvent_Deleted{Deleted: &pb.OrderDeleted{Id: order.GetId(), Order: order}}
+	}
+
+	return msg
+}
+
+func OrderEventFromProto(msg *pb.OrderEvent) (domain.OrderEvent, error) {
+	eventID, err := uuid.Parse(msg.GetEventId())
+	if err != nil {
+		return domain.OrderEvent{}, fmt.Errorf("parse event id: %w", err)
+	}
+
+	event := domain.OrderEvent{
+		ID:         eventID,
+		OccurredAt: msg.GetOccurTime().AsTime(),
+	}
+
+	var order *pb.Order
+	switch payload := msg.GetPayload().(type) {
+	case *pb.OrderEvent_Created:
+		event.Type, order = domain.OrderEventCreated, payload.Created.GetOrder()
+	case *pb.OrderEvent_Updated:
+		event.Type, order = domain.OrderEventUpdated, payload.Updated.GetOrder()
+	case *pb.OrderEvent_Deleted:
+		event.Type, order = domain.OrderEventDeleted, payload.Deleted.GetOrder()
+	default:
+		return domain.OrderEvent{}, fmt.Errorf("unknown order event payload %T", payload)
+	}
+
+	event.Order, err = OrderFromProto(order)
+	if err != nil {
+		return domain.OrderEvent{}, err
+	}
+
+	return event, nil
+}
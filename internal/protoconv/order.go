@@ -0,0 +1,158 @@
+package protoconv
+
+import (
+	"orderservice/internal/domain"
+	pb "orderservice/pkg/api/order"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func OrderToProto(order *domain.Order) *pb.Order {
+	items := make([]*pb.LineItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, &pb.LineItem{
+			Item:     item.Item,
+			Quantity: item.Quantity,
+		})
+	}
+
+	return &pb.Order{
+		Id:         order.ID.String(),
+		CustomerId: order.CustomerID,
+		Status:     OrderStatusToProto(order.Status),
+		Items:      items,
+		CreateTime: timestamppb.New(order.CreatedAt),
+		Etag:       order.ETag(),
+	}
+}
+
+func OrderFromProto(order *pb.Order) (*domain.Order, error) {
+	id, err := uuid.Parse(order.GetId())
+	if err != nil {
+		return nil, domain.ErrInvalidID
+	}
+
+	status, err := OrderStatusFromProto(order.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := domain.ParseETag(order.GetEtag())
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Order{
+		ID:         id,
+		CustomerID: order.GetCustomerId(),
+		Status:     status,
+		Items:      LineItemsFromProto(order.GetItems()),
+		CreatedAt:  order.GetCreateTime().AsTime(),
+		Version:    version,
+	}, nil
+}
+
+func LineItemsFromProto(items []*pb.LineItem) []domain.LineItem {
+	lineItems := make([]domain.LineItem, 0, len(items))
+	for _, item := range items {
+		lineItems = append(lineItems, domain.LineItem{
+			Item:     item.GetItem(),
+			Quantity: item.GetQuantity(),
+		})
+	}
+
+	return lineItems
+}
+
+func OrderStatusToProto(status domain.OrderStatus) pb.OrderStatus {
+	switch status {
+	case domain.OrderStatusPending:
+		return pb.OrderStatus_ORDER_STATUS_PENDING
+	case domain.OrderStatusConfirmed:
+		return pb.OrderStatus_ORDER_STATUS_CONFIRMED
+	case domain.OrderStatusPaid:
+		return pb.OrderStatus_ORDER_STATUS_PAID
+	case domain.OrderStatusShipped:
+		return pb.OrderStatus_ORDER_STATUS_SHIPPED
+	case domain.OrderStatusDelivered:
+		return pb.OrderStatus_ORDER_STATUS_DELIVERED
+	case domain.OrderStatusCancelled:
+		return pb.OrderStatus_ORDER_STATUS_CANCELLED
+	default:
+		return pb.OrderStatus_ORDER_STATUS_UNSPECIFIED
+	}
+}
+
+func OrderStatusFromProto(status pb.OrderStatus) (domain.OrderStatus, error) {
+	switch status {
+	case pb.OrderStatus_ORDER_STATUS_PENDING:
+		return domain.OrderStatusPending, nil
+	case pb.OrderStatus_ORDER_STATUS_CONFIRMED:
+		return domain.OrderStatusConfirmed, nil
+	case pb.OrderStatus_ORDER_STATUS_PAID:
+		return domain.OrderStatusPaid, nil
+	case pb.OrderStatus_ORDER_STATUS_SHIPPED:
+		return domain.OrderStatusShipped, nil
+	case pb.OrderStatus_ORDER_STATUS_DELIVERED:
+		return domain.OrderStatusDelivered, nil
+	case pb.OrderStatus_ORDER_STATUS_CANCELLED:
+		return domain.OrderStatusCancelled, nil
+	case pb.OrderStatus_ORDER_STATUS_UNSPECIFIED:
+		return "", domain.ErrInvalidOrderStatus
+	default:
+		return "", domain.ErrInvalidOrderStatus
+	}
+}
+
+func OrderListQueryFromProto(req *pb.ListOrdersRequest) (domain.OrderListQuery, error) {
+	sort, err := domain.ParseOrderSort(req.GetOrderBy())
+	if err != nil {
+		return domain.OrderListQuery{}, err
+	}
+
+	filter, err := OrderFilterFromProto(req.GetFilter())
+	if err != nil {
+		return domain.OrderListQuery{}, err
+	}
+
+	return domain.OrderListQuery{
+		PageSize:  int(req.GetPageSize()),
+		PageToken: req.GetPageToken(),
+		Filter:    filter,
+		Sort:      sort,
+	}, nil
+}
+
+func OrderFilterFromProto(f *pb.OrderFilter) (domain.OrderFilter, error) {
+	if f == nil {
+		return domain.OrderFilter{}, nil
+	}
+
+	filter := domain.OrderFilter{
+		Item:        f.GetItem(),
+		MinQuantity: f.MinQuantity,
+		MaxQuantity: f.MaxQuantity,
+	}
+	for _, st := range f.GetStatuses() {
+		status, err := OrderStatusFromProto(st)
+		if err != nil {
+			return domain.OrderFilter{}, err
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	if f.GetCreatedAfter() != nil {
+		if err := f.GetCreatedAfter().CheckValid(); err != nil {
+			return domain.OrderFilter{}, domain.NewFieldError(domain.ErrInvalidOrderQuery, "filter.created_after", err.Error())
+		}
+		filter.CreatedAfter = f.GetCreatedAfter().AsTime()
+	}
+	if f.GetCreatedBefore() != nil {
+		if err := f.GetCreatedBefore().CheckValid(); err != nil {
+			return domain.OrderFilter{}, domain.NewFieldError(domain.ErrInvalidOrderQuery, "filter.created_before", err.Error())
+		}
+		filter.CreatedBefore = f.GetCreatedBefore().AsTime()
+	}
+
+	return filter, nil
+}
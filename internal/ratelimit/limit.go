@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidLimit = errors.New("invalid rate limit")
+
+// Limit is a token bucket refilled at Rate tokens per second and holding at
+// most Burst tokens.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+type Store interface {
+	// Take removes one token from the bucket identified by key.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// ParseLimit parses "<requests>/<period>[:<burst>]", e.g. "10/1s:20". The
+// burst defaults to the number of requests.
+func ParseLimit(spec string) (Limit, error) {
+	spec = strings.TrimSpace(spec)
+
+	rateSpec, burstSpec, hasBurst := strings.Cut(spec, ":")
+	countSpec, periodSpec, ok := strings.Cut(rateSpec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("%w: %q: expected <requests>/<period>", ErrInvalidLimit, spec)
+	}
+
+	count, err := strconv.Atoi(countSpec)
+	if err != nil || count <= 0 {
+		return Limit{}, fmt.Errorf("%w: %q: requests must be a positive integer", ErrInvalidLimit, spec)
+	}
+
+	period, err := time.ParseDuration(periodSpec)
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("%w: %q: period must be a positive duration", ErrInvalidLimit, spec)
+	}
+
+	burst := count
+	if hasBurst {
+		burst, err = strconv.Atoi(burstSpec)
+		if err != nil || burst <= 0 {
+			return Limit{}, fmt.Errorf("%w: %q: burst must be a positive integer", ErrInvalidLimit, spec)
+		}
+	}
+
+	return Limit{
+		Rate:  float64(count) / period.Seconds(),
+		Burst: burst,
+	}, nil
+}
+
+// ParseLimits parses a comma-separated list of "<method>=<limit>" pairs.
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		method, limitSpec, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(method) == "" {
+			return nil, fmt.Errorf("%w: %q: expected <method>=<limit>", ErrInvalidLimit, entry)
+		}
+
+		limit, err := ParseLimit(limitSpec)
+		if err != nil {
+			return nil, err
+		}
+		limits[strings.TrimSpace(method)] = limit
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// Limiter takes tokens from a shared store, normally Redis, and falls back
+// to per-process buckets while the shared store is unavailable.
+type Limiter struct {
+	mu       sync.RWMutex
+	shared   Store
+	fallback *MemoryStore
+	degraded atomic.Bool
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{
+		fallback: NewMemoryStore(),
+	}
+}
+
+// SetShared switches the limiter to a store shared by all replicas.
+func (l *Limiter) SetShared(store Store) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.shared = store
+}
+
+func (l *Limiter) Take(ctx context.Context, key string, limit Limit) Result {
+	l.mu.RLock()
+	shared := l.shared
+	l.mu.RUnlock()
+
+	if shared != nil {
+		result, err := shared.Take(ctx, key, limit)
+		if err == nil {
+			if l.degraded.CompareAndSwap(true, false) {
+				slog.InfoContext(ctx, "rate limiter store recovered")
+			}
+			return result
+		}
+
+		if ctx.Err() == nil && l.degraded.CompareAndSwap(false, true) {
+			slog.WarnContext(ctx, "rate limiter store unavailable, using in-process limits", slog.Any("error", err))
+		}
+	}
+
+	result, _ := l.fallback.Take(ctx, key, limit)
+	return result
+}
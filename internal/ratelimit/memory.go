@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// maxBuckets bounds memory when keys are client addresses; the least
+// recently used buckets are dropped once it is exceeded. A dropped bucket
+// starts full again, which only matters for keys idle the longest.
+const maxBuckets = 10000
+
+type bucket struct {
+	key     string
+	tokens  float64
+	updated time.Time
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	// recent orders buckets from most to least recently used.
+	recent *list.List
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*list.Element),
+		recent:  list.New(),
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	var b *bucket
+	if elem, ok := s.buckets[key]; ok {
+		s.recent.MoveToFront(elem)
+		b = elem.Value.(*bucket) //nolint:forcetypeassert // only *bucket is stored
+	} else {
+		for s.recent.Len() >= maxBuckets {
+			s.evict(s.recent.Back())
+		}
+		b = &bucket{key: key, tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = s.recent.PushFront(b)
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return Result{
+			RetryAfter: time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second)),
+		}, nil
+	}
+
+	b.tokens--
+
+	return Result{
+		Allowed:   true,
+		Remaining: int(b.tokens),
+	}, nil
+}
+
+func (s *MemoryStore) evict(elem *list.Element) {
+	s.recent.Remove(elem)
+	delete(s.buckets, elem.Value.(*bucket).key) //nolint:forcetypeassert // only *bucket is stored
+}
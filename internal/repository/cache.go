@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrCacheMiss = errors.New("cache miss")
+
+// CacheStore keeps opaque values by key for the caching decorators.
+type CacheStore interface {
+	// Get returns ErrCacheMiss for absent or expired keys.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// CacheInvalidator tells every replica which cache keys changed so that
+// in-process copies can be dropped.
+type CacheInvalidator interface {
+	Publish(ctx context.Context, key string) error
+	// Subscribe blocks until ctx is done, calling evict for each key
+	// published by another replica and evictAll whenever messages may have
+	// been missed.
+	Subscribe(ctx context.Context, evict func(key string), evictAll func())
+}
@@ -0,0 +1,231 @@
+// Package cache wraps an order repository with a read-through cache kept in
+// any repository.CacheStore.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"orderservice/internal/domain"
+	"orderservice/internal/metrics"
+	"orderservice/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultTTL       = 5 * time.Minute
+	DefaultKeyPrefix = "order:"
+
+	invalidateTimeout = 2 * time.Second
+)
+
+// OrderRepository caches orders by id. Create, Get and Update refresh the
+// cached copy and Delete drops it; List and fresh reads (see
+// repository.WithFreshRead) always reach the wrapped repository. Cache failures are logged and never fail the call.
+type OrderRepository struct {
+	next        repository.OrderRepository
+	store       repository.CacheStore
+	invalidator repository.CacheInvalidator
+	ttl         time.Duration
+	keyPrefix   string
+	metrics     *metrics.Metrics
+}
+
+type Config struct {
+	// TTL defaults to DefaultTTL.
+	TTL time.Duration
+	// KeyPrefix defaults to DefaultKeyPrefix.
+	KeyPrefix string
+	// Invalidator, when set, is told about orders changed by Update and
+	// Delete so that other replicas drop their in-process copies.
+	Invalidator repository.CacheInvalidator
+	Metrics     *metrics.Metrics
+}
+
+func NewOrderRepository(next repository.OrderRepository, store repository.CacheStore, config *Config) *OrderRepository {
+	if config == nil {
+		config = &Config{}
+	}
+
+	repo := &OrderRepository{
+		next:        next,
+		store:       store,
+		invalidator: config.Invalidator,
+		ttl:         config.TTL,
+		keyPrefix:   config.KeyPrefix,
+		metrics:     config.Metrics,
+	}
+	if repo.ttl <= 0 {
+		repo.ttl = DefaultTTL
+	}
+	if repo.keyPrefix == "" {
+		repo.keyPrefix = DefaultKeyPrefix
+	}
+
+	return repo
+}
+
+func (r *OrderRepository) key(id string) string {
+	return r.keyPrefix + id
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	if err := r.next.Create(ctx, order); err != nil {
+		return err
+	}
+
+	_ = r.set(ctx, order)
+	return nil
+}
+
+func (r *OrderRepository) Get(ctx context.Context, id uuid.UUID, customerID string) (*domain.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !repository.IsFreshRead(ctx) {
+		if order, err := r.get(ctx, id.String()); err == nil {
+			if !order.OwnedBy(customerID) {
+				return nil, domain.ErrOrderNotFound
+			}
+			return order, nil
+		} else if !errors.Is(err, repository.ErrCacheMiss) {
+			slog.WarnContext(ctx, "cache get failed", slog.String("order_id", id.String()), slog.Any("error", err))
+		}
+	}
+
+	order, err := r.next.Get(ctx, id, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = r.set(ctx, order)
+	return order, nil
+}
+
+func (r *OrderRepository) Update(ctx context.Context, order *domain.Order, customerID string) error {
+	if err := r.next.Update(ctx, order, customerID); err != nil {
+		r.dropIfStale(ctx, order.ID.String(), err)
+		return err
+	}
+
+	r.refresh(ctx, order)
+	r.publish(ctx, order.ID.String())
+	return nil
+}
+
+func (r *OrderRepository) Delete(ctx context.Context, id uuid.UUID, customerID string, version int64) error {
+	if err := r.next.Delete(ctx, id, customerID, version); err != nil {
+		r.dropIfStale(ctx, id.String(), err)
+		return err
+	}
+
+	r.invalidate(ctx, id.String())
+	r.publish(ctx, id.String())
+	return nil
+}
+
+func (r *OrderRepository) List(ctx context.Context, query domain.OrderListQuery) (*domain.OrderPage, error) {
+	return r.next.List(ctx, query)
+}
+
+func (r *OrderRepository) get(ctx context.Context, id string) (*domain.Order, error) {
+	data, err := r.store.Get(ctx, r.key(id))
+	if err != nil {
+		if errors.Is(err, repository.ErrCacheMiss) {
+			r.metrics.CountCache("get", "miss")
+		} else {
+			r.metrics.CountCache("get", "error")
+		}
+		return nil, err
+	}
+
+	var order domain.Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		r.metrics.CountCache("get", "error")
+		_ = r.store.Delete(ctx, r.key(id))
+		return nil, err
+	}
+
+	r.metrics.CountCache("get", "hit")
+	return &order, nil
+}
+
+func (r *OrderRepository) set(ctx context.Context, order *domain.Order) error {
+	data, err := json.Marshal(order)
+	if err == nil {
+		err = r.store.Set(ctx, r.key(order.ID.String()), data, r.ttl)
+		r.countResult("set", err)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "cache set failed", slog.String("order_id", order.ID.String()), slog.Any("error", err))
+	}
+
+	return err
+}
+
+// refresh caches the order written by Update even if ctx is already
+// canceled. When that fails the cached copy is dropped instead, since it
+// holds the previous version and would fail every later compare-and-swap.
+func (r *OrderRepository) refresh(ctx context.Context, order *domain.Order) {
+	setCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), invalidateTimeout)
+	defer cancel()
+
+	if err := r.set(setCtx, order); err != nil {
+		r.invalidate(ctx, order.ID.String())
+	}
+}
+
+// dropIfStale drops the cached order when a write lost its compare-and-swap,
+// as the cached copy may be what the caller read its version from.
+func (r *OrderRepository) dropIfStale(ctx context.Context, id string, err error) {
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		return
+	}
+
+	r.invalidate(ctx, id)
+	r.publish(ctx, id)
+}
+
+// invalidate drops the cached order even if ctx is already canceled, since
+// the delete it follows has succeeded.
+func (r *OrderRepository) invalidate(ctx context.Context, id string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), invalidateTimeout)
+	defer cancel()
+
+	err := r.store.Delete(ctx, r.key(id))
+	r.countResult("delete", err)
+	if err != nil {
+		slog.WarnContext(ctx, "cache invalidation failed", slog.String("order_id", id), slog.Any("error", err))
+	}
+}
+
+// publish tells other replicas that the order changed. Like invalidate it
+// outlives ctx, since the write it follows has succeeded.
+func (r *OrderRepository) publish(ctx context.Context, id string) {
+	if r.invalidator == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), invalidateTimeout)
+	defer cancel()
+
+	err := r.invalidator.Publish(ctx, r.key(id))
+	r.countResult("publish", err)
+	if err != nil {
+		slog.WarnContext(ctx, "cache invalidation publish failed", slog.String("order_id", id), slog.Any("error", err))
+	}
+}
+
+func (r *OrderRepository) countResult(operation string, err error) {
+	if err != nil {
+		r.metrics.CountCache(operation, "error")
+		return
+	}
+
+	r.metrics.CountCache(operation, "ok")
+}
@@ -0,0 +1,23 @@
+package cache_test
+
+import (
+	"testing"
+
+	"orderservice/internal/repository"
+	"orderservice/internal/repository/cache"
+	"orderservice/internal/repository/inmemory"
+	"orderservice/internal/repository/repositorytest"
+)
+
+func TestOrderRepository(t *testing.T) {
+	repositorytest.Run(t, func(*testing.T) repository.OrderRepository {
+		return cache.NewOrderRepository(inmemory.NewOrderRepository(), inmemory.NewLRUCacheStore(100), nil)
+	})
+}
+
+func TestOrderRepositoryTiered(t *testing.T) {
+	repositorytest.Run(t, func(*testing.T) repository.OrderRepository {
+		store := cache.NewTieredStore(inmemory.NewLRUCacheStore(10), inmemory.NewLRUCacheStore(100), nil)
+		return cache.NewOrderRepository(inmemory.NewOrderRepository(), store, nil)
+	})
+}
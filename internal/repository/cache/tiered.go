@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"hash/maphash"
+	"sync"
+	"time"
+
+	"orderservice/internal/metrics"
+	"orderservice/internal/repository"
+	"orderservice/internal/repository/inmemory"
+)
+
+const (
+	DefaultL1TTL = 30 * time.Second
+
+	generationSlots = 256
+)
+
+// TieredStore serves reads from an in-process LRU (L1) in front of a shared
+// store (L2) and writes through to both. Other replicas keep their own L1,
+// so writers must publish changed keys through a repository.CacheInvalidator
+// and every replica must run Subscribe; an L1 entry missed by invalidation
+// is stale for at most the L1 TTL.
+//
+// An L1 miss refills L1 from L2 only if no write or eviction of the key
+// happened meanwhile, so an invalidation racing with the L2 read can't put
+// the old value back. Keys share generation counters by hash, so a
+// collision only costs a skipped refill.
+type TieredStore struct {
+	l1      *inmemory.LRUCacheStore
+	l2      repository.CacheStore
+	l1TTL   time.Duration
+	metrics *metrics.Metrics
+
+	// mu orders generation bumps with the L1 writes they guard.
+	mu          sync.Mutex
+	seed        maphash.Seed
+	generations [generationSlots]uint64
+}
+
+type TieredConfig struct {
+	// L1TTL caps how long entries live in L1 and defaults to DefaultL1TTL.
+	L1TTL   time.Duration
+	Metrics *metrics.Metrics
+}
+
+func NewTieredStore(l1 *inmemory.LRUCacheStore, l2 repository.CacheStore, config *TieredConfig) *TieredStore {
+	if config == nil {
+		config = &TieredConfig{}
+	}
+
+	store := &TieredStore{
+		l1:      l1,
+		l2:      l2,
+		l1TTL:   config.L1TTL,
+		metrics: config.Metrics,
+		seed:    maphash.MakeSeed(),
+	}
+	if store.l1TTL <= 0 {
+		store.l1TTL = DefaultL1TTL
+	}
+
+	return store
+}
+
+func (s *TieredStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if data, err := s.l1.Get(ctx, key); err == nil {
+		s.metrics.CountCacheTier("l1", "hit")
+		return data, nil
+	}
+	s.metrics.CountCacheTier("l1", "miss")
+
+	slot := s.slot(key)
+	s.mu.Lock()
+	generation := s.generations[slot]
+	s.mu.Unlock()
+
+	data, err := s.l2.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrCacheMiss) {
+			s.metrics.CountCacheTier("l2", "miss")
+		} else {
+			s.metrics.CountCacheTier("l2", "error")
+		}
+		return nil, err
+	}
+	s.metrics.CountCacheTier("l2", "hit")
+
+	s.mu.Lock()
+	if s.generations[slot] == generation {
+		_ = s.l1.Set(ctx, key, data, s.l1TTL)
+	}
+	s.mu.Unlock()
+
+	return data, nil
+}
+
+// Set updates L1 even if L2 fails, since the value is the latest one this
+// replica knows of.
+func (s *TieredStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := s.l2.Set(ctx, key, value, ttl)
+
+	s.mu.Lock()
+	s.generations[s.slot(key)]++
+	_ = s.l1.Set(ctx, key, value, s.l1EntryTTL(ttl))
+	s.mu.Unlock()
+
+	return err
+}
+
+// Delete drops L2 first so that a concurrent refill can't read the value
+// after L1 has been cleared.
+func (s *TieredStore) Delete(ctx context.Context, key string) error {
+	err := s.l2.Delete(ctx, key)
+	s.Evict(key)
+
+	return err
+}
+
+// Evict and EvictAll only drop L1 entries; they are meant as the callbacks
+// of repository.CacheInvalidator.Subscribe.
+func (s *TieredStore) Evict(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generations[s.slot(key)]++
+	_ = s.l1.Delete(context.Background(), key)
+}
+
+func (s *TieredStore) EvictAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.generations {
+		s.generations[i]++
+	}
+	s.l1.Clear()
+}
+
+func (s *TieredStore) slot(key string) uint64 {
+	return maphash.String(s.seed, key) % generationSlots
+}
+
+func (s *TieredStore) l1EntryTTL(ttl time.Duration) time.Duration {
+	if ttl > 0 {
+		return min(ttl, s.l1TTL)
+	}
+
+	return s.l1TTL
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"orderservice/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// OrderCursor is the keyset position encoded into an opaque page token.
+type OrderCursor struct {
+	CreatedAt   time.Time `json:"c"`
+	ID          uuid.UUID `json:"i"`
+	Fingerprint string    `json:"f"`
+}
+
+func EncodeOrderCursor(query domain.OrderListQuery, last *domain.Order) string {
+	data, err := json.Marshal(OrderCursor{
+		CreatedAt:   last.CreatedAt,
+		ID:          last.ID,
+		Fingerprint: queryFingerprint(query),
+	})
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeOrderCursor returns nil when the query has no page token. Tokens issued
+// for a different filter or sort order are rejected.
+func DecodeOrderCursor(query domain.OrderListQuery) (*OrderCursor, error) {
+	if query.PageToken == "" {
+		return nil, nil //nolint:nilnil // absence of a cursor is not an error
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(query.PageToken)
+	if err != nil {
+		return nil, domain.ErrInvalidPageToken
+	}
+
+	var cursor OrderCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, domain.ErrInvalidPageToken
+	}
+
+	if cursor.Fingerprint != queryFingerprint(query) {
+		return nil, fmt.Errorf("%w: token does not match filter or order_by", domain.ErrInvalidPageToken)
+	}
+
+	return &cursor, nil
+}
+
+func (c *OrderCursor) Order() *domain.Order {
+	return &domain.Order{
+		ID:        c.ID,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func queryFingerprint(query domain.OrderListQuery) string {
+	data, _ := json.Marshal(struct {
+		Filter domain.OrderFilter
+		Sort   string
+	}{
+		Filter: query.Filter,
+		Sort:   query.Sort.String(),
+	})
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewOrderPage builds a page from up to query.PageSize+1 sorted orders, the
+// extra one only signalling that another page exists.
+func NewOrderPage(query domain.OrderListQuery, orders []*domain.Order) *domain.OrderPage {
+	page := &domain.OrderPage{Orders: orders}
+	if len(orders) > query.PageSize {
+		page.Orders = orders[:query.PageSize]
+		page.NextPageToken = EncodeOrderCursor(query, page.Orders[len(page.Orders)-1])
+	}
+
+	return page
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"orderservice/internal/domain"
+)
+
+type IdempotencyStore interface {
+	// Reserve stores record under key unless the key is already taken, in
+	// which case the stored record is returned instead.
+	Reserve(ctx context.Context, key string, record domain.IdempotencyRecord, ttl time.Duration) (
+		*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, record domain.IdempotencyRecord, ttl time.Duration) error
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,92 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+
+	"orderservice/internal/domain"
+)
+
+const eventHistorySize = 1024
+
+// EventBroadcaster fans order events out to watchers and keeps the most
+// recent ones so watchers can resume after a reconnect.
+type EventBroadcaster struct {
+	mu      sync.Mutex
+	history []domain.OrderEvent
+	lastSeq int64
+	changed chan struct{}
+}
+
+func NewEventBroadcaster() *EventBroadcaster {
+	return &EventBroadcaster{
+		history: make([]domain.OrderEvent, 0, eventHistorySize),
+		changed: make(chan struct{}),
+	}
+}
+
+func (b *EventBroadcaster) Publish(event domain.OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastSeq++
+	event.Sequence = b.lastSeq
+
+	if len(b.history) == eventHistorySize {
+		b.history = append(b.history[:0], b.history[1:]...)
+	}
+	b.history = append(b.history, event)
+
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+func (b *EventBroadcaster) Watch(ctx context.Context, after int64, fn func(domain.OrderEvent) error) error {
+	b.mu.Lock()
+	if after > b.lastSeq {
+		b.mu.Unlock()
+		return domain.ErrInvalidResumeToken
+	}
+	if after == 0 {
+		after = b.lastSeq
+	}
+	b.mu.Unlock()
+
+	for {
+		events, changed, err := b.since(after)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+			after = event.Sequence
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (b *EventBroadcaster) since(after int64) ([]domain.OrderEvent, <-chan struct{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.history) > 0 && after < b.history[0].Sequence-1 {
+		return nil, nil, domain.ErrResumeTokenExpired
+	}
+
+	var events []domain.OrderEvent
+	for _, event := range b.history {
+		if event.Sequence > after {
+			events = append(events, event)
+		}
+	}
+
+	return events, b.changed, nil
+}
@@ -0,0 +1,107 @@
+package inmemory
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"orderservice/internal/repository"
+)
+
+// LRUCacheStore is a size-bounded in-process cache store that evicts the
+// least recently used entry once full.
+type LRUCacheStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  *list.List
+	byKey    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewLRUCacheStore(capacity int) *LRUCacheStore {
+	return &LRUCacheStore{
+		capacity: max(capacity, 1),
+		entries:  list.New(),
+		byKey:    make(map[string]*list.Element),
+	}
+}
+
+func (s *LRUCacheStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.byKey[key]
+	if !ok {
+		return nil, repository.ErrCacheMiss
+	}
+
+	entry := elem.Value.(*lruEntry) //nolint:forcetypeassert // only *lruEntry is stored
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		s.remove(elem)
+		return nil, repository.ErrCacheMiss
+	}
+
+	s.entries.MoveToFront(elem)
+	return slices.Clone(entry.value), nil
+}
+
+func (s *LRUCacheStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := s.byKey[key]; ok {
+		entry := elem.Value.(*lruEntry) //nolint:forcetypeassert // only *lruEntry is stored
+		entry.value = slices.Clone(value)
+		entry.expiresAt = expiresAt
+		s.entries.MoveToFront(elem)
+		return nil
+	}
+
+	s.byKey[key] = s.entries.PushFront(&lruEntry{
+		key:       key,
+		value:     slices.Clone(value),
+		expiresAt: expiresAt,
+	})
+	for s.entries.Len() > s.capacity {
+		s.remove(s.entries.Back())
+	}
+
+	return nil
+}
+
+func (s *LRUCacheStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.byKey[key]; ok {
+		s.remove(elem)
+	}
+
+	return nil
+}
+
+// Clear drops every entry.
+func (s *LRUCacheStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries.Init()
+	clear(s.byKey)
+}
+
+func (s *LRUCacheStore) remove(elem *list.Element) {
+	s.entries.Remove(elem)
+	delete(s.byKey, elem.Value.(*lruEntry).key) //nolint:forcetypeassert // only *lruEntry is stored
+}
@@ -0,0 +1,90 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"orderservice/internal/domain"
+)
+
+type idempotencyEntry struct {
+	record    domain.IdempotencyRecord
+	expiresAt time.Time
+}
+
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry
+}
+
+func NewIdempotencyStore() *IdempotencyStore {
+	return &IdempotencyStore{
+		entries: make(map[string]idempotencyEntry),
+	}
+}
+
+func (s *IdempotencyStore) Reserve(
+	ctx context.Context,
+	key string,
+	record domain.IdempotencyRecord,
+	ttl time.Duration,
+) (*domain.IdempotencyRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.evictExpired(now)
+
+	if entry, ok := s.entries[key]; ok {
+		existing := entry.record
+		return &existing, nil
+	}
+	s.entries[key] = idempotencyEntry{record: record, expiresAt: now.Add(ttl)}
+
+	return nil, nil //nolint:nilnil // nil record means the key was reserved
+}
+
+func (s *IdempotencyStore) Complete(
+	ctx context.Context,
+	key string,
+	record domain.IdempotencyRecord,
+	ttl time.Duration,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.Completed = true
+	s.entries[key] = idempotencyEntry{record: record, expiresAt: time.Now().Add(ttl)}
+
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
+
+func (s *IdempotencyStore) evictExpired(now time.Time) {
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -2,21 +2,27 @@ package inmemory
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	"orderservice/internal/domain"
+	"orderservice/internal/repository"
 
 	"github.com/google/uuid"
 )
 
+// OrderRepository stores copies of orders, so neither the orders passed in
+// nor the ones handed out alias what it keeps.
 type OrderRepository struct {
 	mu     sync.RWMutex
 	orders map[string]*domain.Order
+	events *EventBroadcaster
 }
 
 func NewOrderRepository() *OrderRepository {
 	return &OrderRepository{
 		orders: make(map[string]*domain.Order),
+		events: NewEventBroadcaster(),
 	}
 }
 
@@ -31,12 +37,13 @@ func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error
 	if _, ok := r.orders[order.ID.String()]; ok {
 		return domain.ErrOrderAlreadyExist
 	}
-	r.orders[order.ID.String()] = order
+	r.orders[order.ID.String()] = order.Clone()
+	r.events.Publish(domain.NewOrderEvent(domain.OrderEventCreated, order))
 
 	return nil
 }
 
-func (r *OrderRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+func (r *OrderRepository) Get(ctx context.Context, id uuid.UUID, customerID string) (*domain.Order, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -45,14 +52,14 @@ func (r *OrderRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Order,
 	defer r.mu.RUnlock()
 
 	order, ok := r.orders[id.String()]
-	if !ok {
+	if !ok || !order.OwnedBy(customerID) {
 		return nil, domain.ErrOrderNotFound
 	}
 
-	return order, nil
+	return order.Clone(), nil
 }
 
-func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error {
+func (r *OrderRepository) Update(ctx context.Context, order *domain.Order, customerID string) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -60,15 +67,24 @@ func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.orders[order.ID.String()]; !ok {
+	current, ok := r.orders[order.ID.String()]
+	if !ok || !current.OwnedBy(customerID) {
 		return domain.ErrOrderNotFound
 	}
-	r.orders[order.ID.String()] = order
+	if current.Version != order.Version {
+		return domain.ErrVersionMismatch
+	}
+
+	// Ownership never changes, matching the SQL repositories.
+	order.CustomerID = current.CustomerID
+	order.Version++
+	r.orders[order.ID.String()] = order.Clone()
+	r.events.Publish(domain.NewOrderEvent(domain.OrderEventUpdated, order))
 
 	return nil
 }
 
-func (r *OrderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *OrderRepository) Delete(ctx context.Context, id uuid.UUID, customerID string, version int64) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -76,27 +92,72 @@ func (r *OrderRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.orders[id.String()]; !ok {
+	current, ok := r.orders[id.String()]
+	if !ok || !current.OwnedBy(customerID) {
 		return domain.ErrOrderNotFound
 	}
+	if version != domain.AnyVersion && current.Version != version {
+		return domain.ErrVersionMismatch
+	}
 
 	delete(r.orders, id.String())
+	r.events.Publish(domain.NewOrderEvent(domain.OrderEventDeleted, current))
 
 	return nil
 }
 
-func (r *OrderRepository) List(ctx context.Context) ([]*domain.Order, error) {
+func (r *OrderRepository) List(ctx context.Context, query domain.OrderListQuery) (*domain.OrderPage, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	cursor, err := repository.DecodeOrderCursor(query)
+	if err != nil {
+		return nil, err
+	}
 
+	r.mu.RLock()
 	orders := make([]*domain.Order, 0, len(r.orders))
 	for _, order := range r.orders {
-		orders = append(orders, order)
+		if query.Filter.Matches(order) {
+			orders = append(orders, order.Clone())
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(orders, func(a, b *domain.Order) int {
+		switch {
+		case query.Sort.Less(a, b):
+			return -1
+		case query.Sort.Less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	if cursor != nil {
+		anchor := cursor.Order()
+		start := slices.IndexFunc(orders, func(o *domain.Order) bool {
+			return query.Sort.Less(anchor, o)
+		})
+		if start < 0 {
+			start = len(orders)
+		}
+		orders = orders[start:]
 	}
 
-	return orders, nil
+	if len(orders) > query.PageSize+1 {
+		orders = orders[:query.PageSize+1]
+	}
+
+	return repository.NewOrderPage(query, orders), nil
+}
+
+func (r *OrderRepository) Watch(ctx context.Context, after int64, fn func(domain.OrderEvent) error) error {
+	return r.events.Watch(ctx, after, fn)
 }
@@ -0,0 +1,15 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"orderservice/internal/repository"
+	"orderservice/internal/repository/inmemory"
+	"orderservice/internal/repository/repositorytest"
+)
+
+func TestOrderRepository(t *testing.T) {
+	repositorytest.Run(t, func(*testing.T) repository.OrderRepository {
+		return inmemory.NewOrderRepository()
+	})
+}
@@ -8,10 +8,38 @@ import (
 	"github.com/google/uuid"
 )
 
+// OrderRepository methods taking a customerID only see orders owned by that
+// customer unless it is domain.AnyCustomer; orders of other customers are
+// reported as domain.ErrOrderNotFound. List is scoped by Filter.CustomerID.
 type OrderRepository interface {
 	Create(ctx context.Context, order *domain.Order) error
-	Get(ctx context.Context, id uuid.UUID) (*domain.Order, error)
-	Update(ctx context.Context, order *domain.Order) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context) ([]*domain.Order, error)
+	Get(ctx context.Context, id uuid.UUID, customerID string) (*domain.Order, error)
+	// Update stores the order only if the stored version still equals
+	// order.Version, then bumps order.Version.
+	Update(ctx context.Context, order *domain.Order, customerID string) error
+	// Delete removes the order only if its stored version equals version,
+	// unless version is domain.AnyVersion.
+	Delete(ctx context.Context, id uuid.UUID, customerID string, version int64) error
+	List(ctx context.Context, query domain.OrderListQuery) (*domain.OrderPage, error)
+}
+
+type freshReadKey struct{}
+
+// WithFreshRead marks Get calls made with the returned context as needing
+// the stored order rather than a cached copy, as reads preceding an Update
+// do: a stale version would fail the compare-and-swap.
+func WithFreshRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadKey{}, true)
+}
+
+func IsFreshRead(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadKey{}).(bool)
+	return fresh
+}
+
+type OrderWatcher interface {
+	// Watch calls fn for every order event stored after the event with
+	// sequence number after, or for events stored from now on when after is 0.
+	// It blocks until ctx is done or fn returns an error.
+	Watch(ctx context.Context, after int64, fn func(domain.OrderEvent) error) error
 }
@@ -3,53 +3,57 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"strconv"
 	"time"
 
 	"orderservice/internal/domain"
+	"orderservice/internal/metrics"
+	"orderservice/internal/outbox"
+	"orderservice/internal/repository"
+	"orderservice/internal/repository/sqlorder"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	"github.com/redis/go-redis/v9"
+	"github.com/lib/pq"
 )
 
-const (
-	orderCachePrefix = "order:"
-	cacheTTL         = 5 * time.Minute
-)
+const tracerName = "orderservice/internal/repository/postgres"
+
+var dialect = sqlorder.Dialect{
+	Placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+	EncodeTime:  func(t time.Time) any { return t },
+}
 
 type OrderRepository struct {
-	db          *sqlx.DB
-	redisClient *redis.Client
-	cacheEnable bool
+	db           *sqlx.DB
+	outboxEnable bool
+	observer     sqlorder.Observer
 }
 
 type Config struct {
-	CacheEnable bool
+	OutboxEnable bool
+	Metrics      *metrics.Metrics
 }
 
-func NewOrderRepository(db *sqlx.DB, redisClient *redis.Client, config *Config) *OrderRepository {
+func NewOrderRepository(db *sqlx.DB, config *Config) *OrderRepository {
 	if config == nil {
-		config = &Config{
-			CacheEnable: true,
-		}
+		config = &Config{}
 	}
 
 	return &OrderRepository{
-		db:          db,
-		redisClient: redisClient,
-		cacheEnable: config.CacheEnable,
+		db:           db,
+		outboxEnable: config.OutboxEnable,
+		observer:     sqlorder.Observer{TracerName: tracerName, Metrics: config.Metrics},
 	}
 }
 
-func (r *OrderRepository) cacheKey(id string) string {
-	return orderCachePrefix + id
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	return r.observer.Observe(ctx, "create", func(ctx context.Context) error { return r.insert(ctx, order) })
 }
 
-func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+func (r *OrderRepository) insert(ctx context.Context, order *domain.Order) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -57,187 +61,210 @@ func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error
 	defer tx.Rollback()
 
 	query := `
-		insert into orders (id, item, quantity)
-		values (:id, :item, :quantity)
+		insert into orders (id, customer_id, status, created_at, version)
+		values (:id, :customer_id, :status, :created_at, :version)
 	`
 
 	if _, err := tx.NamedExecContext(ctx, query, order); err != nil {
+		if isPrimaryKeyViolation(err) {
+			return domain.ErrOrderAlreadyExist
+		}
 		return fmt.Errorf("create order: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+	if err := sqlorder.InsertItems(ctx, dialect, tx, order); err != nil {
+		return err
 	}
 
-	if r.cacheEnable {
-		if err := r.setCacheWithRetry(ctx, order); err != nil {
-			log.Printf("warn: cache set error for order %s: %v", order.ID, err)
+	if r.outboxEnable {
+		if err := r.enqueueEvent(ctx, tx, domain.OrderEventCreated, order); err != nil {
+			return err
 		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
 	return nil
 }
 
-func (r *OrderRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
-	if r.cacheEnable {
-		if order, err := r.getFromCache(ctx, id.String()); err == nil {
-			return order, nil
-		} else if !errors.Is(err, redis.Nil) {
-			log.Printf("warn: cache get error for order %s: %v", id, err)
-		}
+func (r *OrderRepository) Get(ctx context.Context, id uuid.UUID, customerID string) (*domain.Order, error) {
+	var order *domain.Order
+	err := r.observer.Observe(ctx, "get", func(ctx context.Context) error {
+		var err error
+		order, err = r.selectByID(ctx, id, customerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return order, nil
+}
+
+func (r *OrderRepository) selectByID(ctx context.Context, id uuid.UUID, customerID string) (*domain.Order, error) {
 	const query = `
-		select id, item, quantity
+		select id, customer_id, status, created_at, version
 		from orders
-		where id = $1
+		where id = $1 and ($2 = '' or customer_id = $2)
 	`
 
 	var order domain.Order
-	if err := r.db.GetContext(ctx, &order, query, id); err != nil {
+	if err := r.db.GetContext(ctx, &order, query, id, customerID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrOrderNotFound
 		}
 		return nil, fmt.Errorf("get order by id: %w", err)
 	}
 
-	if r.cacheEnable {
-		if err := r.setCacheWithRetry(ctx, &order); err != nil {
-			log.Printf("warn: cache set error for order %s: %v", id, err)
-		}
+	if err := sqlorder.LoadItems(ctx, dialect, r.db, []*domain.Order{&order}); err != nil {
+		return nil, err
 	}
 
 	return &order, nil
 }
 
-func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error {
+func (r *OrderRepository) Update(ctx context.Context, order *domain.Order, customerID string) error {
+	return r.observer.Observe(ctx, "update", func(ctx context.Context) error { return r.update(ctx, order, customerID) })
+}
+
+func (r *OrderRepository) update(ctx context.Context, order *domain.Order, customerID string) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	query := `
+	const query = `
 		update orders 
-		set item = :item, quantity = :quantity
-		where id = :id
+		set status = $1, version = version + 1
+		where id = $2 and version = $3 and ($4 = '' or customer_id = $4)
+		returning customer_id
 	`
 
-	result, err := tx.NamedExecContext(ctx, query, order)
-	if err != nil {
+	// Ownership never changes; hand the stored owner back so that callers
+	// caching the order see the real one.
+	var owner string
+	if err := tx.GetContext(ctx, &owner, query, order.Status, order.ID, order.Version, customerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sqlorder.ConflictError(ctx, dialect, tx, order.ID, customerID)
+		}
 		return fmt.Errorf("update order: %w", err)
 	}
+	order.CustomerID = owner
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("get rows affected: %w", err)
+	if _, err := sqlorder.DeleteItems(ctx, dialect, tx, order.ID); err != nil {
+		return err
+	}
+	if err := sqlorder.InsertItems(ctx, dialect, tx, order); err != nil {
+		return err
 	}
 
-	if rowsAffected == 0 {
-		return domain.ErrOrderNotFound
+	if r.outboxEnable {
+		updated := *order
+		updated.Version++
+		if err := r.enqueueEvent(ctx, tx, domain.OrderEventUpdated, &updated); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
 	}
-
-	if r.cacheEnable {
-		if err := r.setCacheWithRetry(ctx, order); err != nil {
-			log.Printf("warn: cache set error for order %s: %v", order.ID, err)
-		}
-	}
+	order.Version++
 
 	return nil
 }
 
-func (r *OrderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *OrderRepository) Delete(ctx context.Context, id uuid.UUID, customerID string, version int64) error {
+	return r.observer.Observe(ctx, "delete", func(ctx context.Context) error {
+		return r.delete(ctx, id, customerID, version)
+	})
+}
+
+func (r *OrderRepository) delete(ctx context.Context, id uuid.UUID, customerID string, version int64) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	items, err := sqlorder.DeleteItems(ctx, dialect, tx, id)
+	if err != nil {
+		return err
+	}
+
 	const query = `
 		delete from orders
-		where id = $1
+		where id = $1 and ($2::bigint = 0 or version = $2::bigint) and ($3 = '' or customer_id = $3)
+		returning id, customer_id, status, created_at, version
 	`
 
-	result, err := tx.ExecContext(ctx, query, id)
-	if err != nil {
+	var order domain.Order
+	if err := tx.GetContext(ctx, &order, query, id, version, customerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sqlorder.ConflictError(ctx, dialect, tx, id, customerID)
+		}
 		return fmt.Errorf("delete order: %w", err)
 	}
+	order.Items = items
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return domain.ErrOrderNotFound
+	if r.outboxEnable {
+		if err := r.enqueueEvent(ctx, tx, domain.OrderEventDeleted, &order); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
 	}
 
-	r.invalidateCache(ctx, id.String())
 	return nil
 }
 
-func (r *OrderRepository) List(ctx context.Context) ([]*domain.Order, error) {
-	const query = `
-		select id, item, quantity
-		from orders
-		order by id
-	`
-
-	var orders []*domain.Order
-	if err := r.db.SelectContext(ctx, &orders, query); err != nil {
-		return nil, fmt.Errorf("list orders: %w", err)
+func (r *OrderRepository) List(ctx context.Context, query domain.OrderListQuery) (*domain.OrderPage, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
 	}
 
-	return orders, nil
-}
-
-func (r *OrderRepository) getFromCache(ctx context.Context, id string) (*domain.Order, error) {
-	data, err := r.redisClient.Get(ctx, r.cacheKey(id)).Bytes()
+	cursor, err := repository.DecodeOrderCursor(query)
 	if err != nil {
 		return nil, err
 	}
 
-	var order domain.Order
-	if err := json.Unmarshal(data, &order); err != nil {
-		r.redisClient.Del(ctx, r.cacheKey(id))
+	sqlQuery, args := sqlorder.BuildListQuery(dialect, query, cursor)
+
+	var orders []*domain.Order
+	err = r.observer.Observe(ctx, "list", func(ctx context.Context) error {
+		if err := r.db.SelectContext(ctx, &orders, sqlQuery, args...); err != nil {
+			return fmt.Errorf("list orders: %w", err)
+		}
+
+		return sqlorder.LoadItems(ctx, dialect, r.db, orders)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &order, nil
+	return repository.NewOrderPage(query, orders), nil
 }
 
-func (r *OrderRepository) setCacheWithRetry(ctx context.Context, order *domain.Order) error {
-	data, err := json.Marshal(order)
+func (r *OrderRepository) enqueueEvent(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	eventType domain.OrderEventType,
+	order *domain.Order,
+) error {
+	msg, err := outbox.NewMessage(domain.NewOrderEvent(eventType, order))
 	if err != nil {
 		return err
 	}
 
-	key := r.cacheKey(order.ID.String())
-
-	err = r.redisClient.Set(ctx, key, data, cacheTTL).Err()
-
-	return err
+	return outbox.Enqueue(ctx, tx, msg)
 }
 
-func (r *OrderRepository) invalidateCache(_ context.Context, id string) {
-	if !r.cacheEnable {
-		return
-	}
-
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), r.redisClient.Options().ReadTimeout)
-		defer cancel()
-
-		if err := r.redisClient.Del(ctx, r.cacheKey(id)).Err(); err != nil {
-			log.Printf("warn: cache invalidation failed for order %s: %v", id, err)
-		}
-	}()
+func isPrimaryKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" && pqErr.Constraint == "orders_pkey"
 }
@@ -0,0 +1,34 @@
+package postgres_test
+
+import (
+	"os"
+	"testing"
+
+	"orderservice/internal/repository"
+	"orderservice/internal/repository/postgres"
+	"orderservice/internal/repository/repositorytest"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// TEST_POSTGRES_DSN points at a database migrated with cmd/migrate. Orders
+// created by the suite are left behind.
+const dsnEnv = "TEST_POSTGRES_DSN"
+
+func TestOrderRepository(t *testing.T) {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		t.Skip(dsnEnv + " is not set")
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("connect to database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	repositorytest.Run(t, func(*testing.T) repository.OrderRepository {
+		return postgres.NewOrderRepository(db, nil)
+	})
+}
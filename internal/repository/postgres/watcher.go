@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"orderservice/internal/domain"
+	"orderservice/internal/outbox"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	orderEventsChannel      = "order_events"
+	watchBatchSize          = 500
+	watchPollInterval       = 5 * time.Second
+	listenerMinReconnect    = 100 * time.Millisecond
+	listenerMaxReconnect    = 10 * time.Second
+	listenerPingInterval    = 30 * time.Second
+	subscriberWakeupBacklog = 1
+	// Outbox ids are allocated before commit, so a missing id may still be
+	// committed by a slower transaction. Gaps younger than gapTimeout are
+	// waited for; older ones are treated as rolled back.
+	gapTimeout    = 5 * time.Second
+	gapRetryDelay = 200 * time.Millisecond
+)
+
+// OrderWatcher streams order events from the outbox table. A single LISTEN
+// connection wakes all subscribers, which then read new outbox rows by id,
+// so the outbox id doubles as the resume position.
+type OrderWatcher struct {
+	db       *sqlx.DB
+	listener *pq.Listener
+	closed   chan struct{}
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func NewOrderWatcher(db *sqlx.DB, connStr string) *OrderWatcher {
+	w := &OrderWatcher{
+		db:          db,
+		closed:      make(chan struct{}),
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+	w.listener = pq.NewListener(connStr, listenerMinReconnect, listenerMaxReconnect,
+		func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				slog.Warn("order events listener error", slog.Any("error", err))
+			}
+			if event == pq.ListenerEventReconnected {
+				// Notifications may have been missed while disconnected.
+				w.wakeAll()
+			}
+		})
+
+	return w
+}
+
+// Run listens for outbox notifications until ctx is done, then ends every
+// active Watch call.
+func (w *OrderWatcher) Run(ctx context.Context) error {
+	defer close(w.closed)
+
+	defer w.listener.Close()
+	if err := w.listener.Listen(orderEventsChannel); err != nil {
+		return fmt.Errorf("listen %s: %w", orderEventsChannel, err)
+	}
+
+	ping := time.NewTicker(listenerPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.listener.Notify:
+			w.wakeAll()
+		case <-ping.C:
+			if err := w.listener.Ping(); err != nil {
+				slog.Warn("order events listener ping failed", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (w *OrderWatcher) Watch(ctx context.Context, after int64, fn func(domain.OrderEvent) error) error {
+	wake := w.subscribe()
+	defer w.unsubscribe(wake)
+
+	last, err := w.startPosition(ctx, after)
+	if err != nil {
+		return err
+	}
+
+	poll := time.NewTicker(watchPollInterval)
+	defer poll.Stop()
+
+	for {
+		messages, err := w.fetch(ctx, last)
+		if err != nil {
+			return err
+		}
+
+		delivered, gap := 0, false
+		for _, msg := range messages {
+			if msg.ID != last+1 && time.Since(msg.CreatedAt) < gapTimeout {
+				gap = true
+				break
+			}
+
+			event, err := msg.Event()
+			if err != nil {
+				return err
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+			last = msg.ID
+			delivered++
+		}
+
+		if !gap && delivered == watchBatchSize {
+			continue
+		}
+
+		var retry <-chan time.Time
+		if gap {
+			retry = time.After(gapRetryDelay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.closed:
+			return nil
+		case <-wake:
+		case <-poll.C:
+		case <-retry:
+		}
+	}
+}
+
+// startPosition validates a resume token against the retained outbox rows.
+// Tokens are ids from order_outbox_id_seq; a token whose successor has been
+// pruned by the relay's retention has expired, since the events after it
+// can no longer be replayed.
+func (w *OrderWatcher) startPosition(ctx context.Context, after int64) (int64, error) {
+	const query = `
+		select
+			coalesce(min(id), 0) as oldest,
+			coalesce(max(id), 0) as latest,
+			(select last_value from order_outbox_id_seq) as issued
+		from order_outbox
+	`
+
+	var bounds struct {
+		Oldest int64 `db:"oldest"`
+		Latest int64 `db:"latest"`
+		Issued int64 `db:"issued"`
+	}
+	if err := w.db.GetContext(ctx, &bounds, query); err != nil {
+		return 0, fmt.Errorf("get retained order events: %w", err)
+	}
+
+	if after == 0 {
+		return bounds.Latest, nil
+	}
+	if after > bounds.Issued {
+		return 0, domain.ErrInvalidResumeToken
+	}
+
+	oldest := bounds.Oldest
+	if oldest == 0 {
+		oldest = bounds.Issued + 1
+	}
+	if after+1 < oldest {
+		return 0, domain.ErrResumeTokenExpired
+	}
+
+	return after, nil
+}
+
+func (w *OrderWatcher) fetch(ctx context.Context, after int64) ([]*outbox.Message, error) {
+	const query = `
+		select id, event_id, aggregate_id, event_type, payload, created_at
+		from order_outbox
+		where id > $1
+		order by id
+		limit $2
+	`
+
+	var messages []*outbox.Message
+	if err := w.db.SelectContext(ctx, &messages, query, after, watchBatchSize); err != nil {
+		return nil, fmt.Errorf("list order events: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (w *OrderWatcher) subscribe() chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wake := make(chan struct{}, subscriberWakeupBacklog)
+	w.subscribers[wake] = struct{}{}
+
+	return wake
+}
+
+func (w *OrderWatcher) unsubscribe(wake chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.subscribers, wake)
+}
+
+func (w *OrderWatcher) wakeAll() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for wake := range w.subscribers {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"orderservice/internal/repository"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type CacheStore struct {
+	client *goredis.Client
+}
+
+func NewCacheStore(client *goredis.Client) *CacheStore {
+	return &CacheStore{
+		client: client,
+	}
+}
+
+func (s *CacheStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, repository.ErrCacheMiss
+	}
+
+	return data, err
+}
+
+func (s *CacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *CacheStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"orderservice/internal/domain"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const idempotencyKeyPrefix = "idempotency:create_order:"
+
+type IdempotencyStore struct {
+	client *goredis.Client
+}
+
+func NewIdempotencyStore(client *goredis.Client) *IdempotencyStore {
+	return &IdempotencyStore{
+		client: client,
+	}
+}
+
+func (s *IdempotencyStore) key(key string) string {
+	return idempotencyKeyPrefix + key
+}
+
+func (s *IdempotencyStore) Reserve(
+	ctx context.Context,
+	key string,
+	record domain.IdempotencyRecord,
+	ttl time.Duration,
+) (*domain.IdempotencyRecord, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	// SET NX GET either claims the key or returns the record that holds it, atomically.
+	existing, err := s.client.SetArgs(ctx, s.key(key), data, goredis.SetArgs{
+		Mode: "NX",
+		TTL:  ttl,
+		Get:  true,
+	}).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil //nolint:nilnil // nil record means the key was reserved
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	var stored domain.IdempotencyRecord
+	if err := json.Unmarshal(existing, &stored); err != nil {
+		return nil, fmt.Errorf("decode idempotency record: %w", err)
+	}
+
+	return &stored, nil
+}
+
+func (s *IdempotencyStore) Complete(
+	ctx context.Context,
+	key string,
+	record domain.IdempotencyRecord,
+	ttl time.Duration,
+) error {
+	record.Completed = true
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.Set(ctx, s.key(key), data, ttl).Err(); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.key(key)).Err(); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// CacheInvalidator broadcasts changed cache keys over a Redis pub/sub
+// channel. Messages are "<origin> <key>", where origin identifies the
+// publishing process so that it can skip its own messages.
+type CacheInvalidator struct {
+	client  *goredis.Client
+	channel string
+	origin  string
+}
+
+func NewCacheInvalidator(client *goredis.Client, channel string) *CacheInvalidator {
+	return &CacheInvalidator{
+		client:  client,
+		channel: channel,
+		origin:  uuid.NewString(),
+	}
+}
+
+func (i *CacheInvalidator) Publish(ctx context.Context, key string) error {
+	return i.client.Publish(ctx, i.channel, i.origin+" "+key).Err()
+}
+
+// Subscribe keeps the subscription alive across reconnects. Pub/sub does
+// not buffer messages for disconnected subscribers, so evictAll is called
+// on every subscription confirmation after the first.
+func (i *CacheInvalidator) Subscribe(ctx context.Context, evict func(key string), evictAll func()) {
+	pubsub := i.client.Subscribe(ctx, i.channel)
+	defer pubsub.Close()
+
+	subscribed := false
+	messages := pubsub.ChannelWithSubscriptions()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			switch msg := msg.(type) {
+			case *goredis.Subscription:
+				if subscribed {
+					evictAll()
+				}
+				subscribed = true
+			case *goredis.Message:
+				origin, key, found := strings.Cut(msg.Payload, " ")
+				if found && origin != i.origin {
+					evict(key)
+				}
+			}
+		}
+	}
+}
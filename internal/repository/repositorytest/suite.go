@@ -0,0 +1,579 @@
+// Package repositorytest holds the behaviour every repository.OrderRepository
+// implementation must share. Implementations wire it from their own tests:
+//
+//	func TestOrderRepository(t *testing.T) {
+//		repositorytest.Run(t, func(t *testing.T) repository.OrderRepository {
+//			return inmemory.NewOrderRepository()
+//		})
+//	}
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"orderservice/internal/domain"
+	"orderservice/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const concurrentWriters = 16
+
+// Factory returns the repository under test. It may hand out repositories
+// sharing one database: every test works on orders of its own customer.
+type Factory func(t *testing.T) repository.OrderRepository
+
+// Run runs the whole suite against repositories built by newRepo.
+func Run(t *testing.T, newRepo Factory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo repository.OrderRepository)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"CreateDuplicate", testCreateDuplicate},
+		{"GetNotFound", testGetNotFound},
+		{"GetOtherCustomer", testGetOtherCustomer},
+		{"Update", testUpdate},
+		{"UpdateVersionMismatch", testUpdateVersionMismatch},
+		{"UpdateNotFound", testUpdateNotFound},
+		{"UpdateKeepsOwner", testUpdateKeepsOwner},
+		{"Delete", testDelete},
+		{"DeleteVersionMismatch", testDeleteVersionMismatch},
+		{"DeleteNotFound", testDeleteNotFound},
+		{"ListOrder", testListOrder},
+		{"ListPagination", testListPagination},
+		{"ListFilter", testListFilter},
+		{"ListInvalidPageToken", testListInvalidPageToken},
+		{"NoAliasing", testNoAliasing},
+		{"ContextCanceled", testContextCanceled},
+		{"ConcurrentCreates", testConcurrentCreates},
+		{"ConcurrentUpdates", testConcurrentUpdates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepo(t))
+		})
+	}
+}
+
+func testCreateAndGet(t *testing.T, repo repository.OrderRepository) {
+	ctx := t.Context()
+	customer := newCustomer()
+	order := newOrder(t, customer, domain.LineItem{Item: "apple", Quantity: 2}, domain.LineItem{Item: "pear", Quantity: 1})
+
+	mustCreate(t, repo, order)
+
+	for _, scope := range []string{customer, domain.AnyCustomer} {
+		got, err := repo.Get(ctx, order.ID, scope)
+		if err != nil {
+			t.Fatalf("Get(scope %q): %v", scope, err)
+		}
+		assertOrder(t, got, order)
+	}
+}
+
+func testCreateDuplicate(t *testing.T, repo repository.OrderRepository) {
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	duplicate := newOrder(t, order.CustomerID, domain.LineItem{Item: "pear", Quantity: 3})
+	duplicate.ID = order.ID
+
+	if err := repo.Create(t.Context(), duplicate); !errors.Is(err, domain.ErrOrderAlreadyExist) {
+		t.Fatalf("Create(duplicate) = %v, want %v", err, domain.ErrOrderAlreadyExist)
+	}
+
+	got := mustGet(t, repo, order.ID)
+	assertOrder(t, got, order)
+}
+
+func testGetNotFound(t *testing.T, repo repository.OrderRepository) {
+	if _, err := repo.Get(t.Context(), uuid.New(), domain.AnyCustomer); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Fatalf("Get(missing) = %v, want %v", err, domain.ErrOrderNotFound)
+	}
+}
+
+func testGetOtherCustomer(t *testing.T, repo repository.OrderRepository) {
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	if _, err := repo.Get(t.Context(), order.ID, newCustomer()); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Fatalf("Get(other customer) = %v, want %v", err, domain.ErrOrderNotFound)
+	}
+}
+
+func testUpdate(t *testing.T, repo repository.OrderRepository) {
+	ctx := t.Context()
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	update := mustGet(t, repo, order.ID)
+	if err := update.Transition(domain.OrderStatusConfirmed); err != nil {
+		t.Fatal(err)
+	}
+	update.Items = []domain.LineItem{{Item: "pear", Quantity: 4}, {Item: "plum", Quantity: 5}}
+
+	if err := repo.Update(ctx, update, order.CustomerID); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if update.Version != order.Version+1 {
+		t.Errorf("Update left version %d, want %d", update.Version, order.Version+1)
+	}
+
+	got := mustGet(t, repo, order.ID)
+	assertOrder(t, got, update)
+}
+
+func testUpdateVersionMismatch(t *testing.T, repo repository.OrderRepository) {
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	first := mustGet(t, repo, order.ID)
+	stale := mustGet(t, repo, order.ID)
+	if err := repo.Update(t.Context(), first, domain.AnyCustomer); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	stale.Items = []domain.LineItem{{Item: "stale", Quantity: 1}}
+	if err := repo.Update(t.Context(), stale, domain.AnyCustomer); !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("Update(stale) = %v, want %v", err, domain.ErrVersionMismatch)
+	}
+
+	assertOrder(t, mustGet(t, repo, order.ID), first)
+}
+
+func testUpdateNotFound(t *testing.T, repo repository.OrderRepository) {
+	ctx := t.Context()
+
+	missing := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	if err := repo.Update(ctx, missing, domain.AnyCustomer); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Fatalf("Update(missing) = %v, want %v", err, domain.ErrOrderNotFound)
+	}
+
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	update := mustGet(t, repo, order.ID)
+	if err := repo.Update(ctx, update, newCustomer()); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Fatalf("Update(other customer) = %v, want %v", err, domain.ErrOrderNotFound)
+	}
+}
+
+func testUpdateKeepsOwner(t *testing.T, repo repository.OrderRepository) {
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	update := mustGet(t, repo, order.ID)
+	update.CustomerID = newCustomer()
+	if err := repo.Update(t.Context(), update, domain.AnyCustomer); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	if got := mustGet(t, repo, order.ID); got.CustomerID != order.CustomerID {
+		t.Fatalf("Update changed owner to %q, want %q", got.CustomerID, order.CustomerID)
+	}
+}
+
+func testDelete(t *testing.T, repo repository.OrderRepository) {
+	ctx := t.Context()
+
+	versioned := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, versioned)
+	if err := repo.Delete(ctx, versioned.ID, versioned.CustomerID, versioned.Version); err != nil {
+		t.Fatalf("Delete(version %d): %v", versioned.Version, err)
+	}
+
+	unversioned := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, unversioned)
+	if err := repo.Delete(ctx, unversioned.ID, domain.AnyCustomer, domain.AnyVersion); err != nil {
+		t.Fatalf("Delete(any version): %v", err)
+	}
+
+	for _, id := range []uuid.UUID{versioned.ID, unversioned.ID} {
+		if _, err := repo.Get(ctx, id, domain.AnyCustomer); !errors.Is(err, domain.ErrOrderNotFound) {
+			t.Fatalf("Get(deleted) = %v, want %v", err, domain.ErrOrderNotFound)
+		}
+	}
+}
+
+func testDeleteVersionMismatch(t *testing.T, repo repository.OrderRepository) {
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	err := repo.Delete(t.Context(), order.ID, domain.AnyCustomer, order.Version+1)
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("Delete(stale version) = %v, want %v", err, domain.ErrVersionMismatch)
+	}
+
+	assertOrder(t, mustGet(t, repo, order.ID), order)
+}
+
+func testDeleteNotFound(t *testing.T, repo repository.OrderRepository) {
+	ctx := t.Context()
+
+	err := repo.Delete(ctx, uuid.New(), domain.AnyCustomer, domain.AnyVersion)
+	if !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Fatalf("Delete(missing) = %v, want %v", err, domain.ErrOrderNotFound)
+	}
+
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	if err := repo.Delete(ctx, order.ID, newCustomer(), domain.AnyVersion); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Fatalf("Delete(other customer) = %v, want %v", err, domain.ErrOrderNotFound)
+	}
+	mustGet(t, repo, order.ID)
+}
+
+func testListOrder(t *testing.T, repo repository.OrderRepository) {
+	customer := newCustomer()
+
+	// Orders sharing a creation time are ordered by id.
+	createdAt := time.Now().UTC().Truncate(time.Microsecond)
+	var orders []*domain.Order
+	for i := range 6 {
+		order := newOrder(t, customer, domain.LineItem{Item: "apple", Quantity: 1})
+		order.CreatedAt = createdAt.Add(time.Duration(i/2) * time.Second)
+		mustCreate(t, repo, order)
+		orders = append(orders, order)
+	}
+
+	byCreateTime := slices.Clone(orders)
+	slices.SortFunc(byCreateTime, func(a, b *domain.Order) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return slices.Compare(a.ID[:], b.ID[:])
+	})
+	byID := slices.Clone(orders)
+	slices.SortFunc(byID, func(a, b *domain.Order) int { return slices.Compare(a.ID[:], b.ID[:]) })
+
+	tests := []struct {
+		sort domain.OrderSort
+		want []*domain.Order
+	}{
+		{domain.OrderSort{Field: domain.OrderSortByCreateTime}, byCreateTime},
+		{domain.OrderSort{Field: domain.OrderSortByCreateTime, Desc: true}, reversed(byCreateTime)},
+		{domain.OrderSort{Field: domain.OrderSortByID}, byID},
+		{domain.OrderSort{Field: domain.OrderSortByID, Desc: true}, reversed(byID)},
+	}
+	for _, tt := range tests {
+		got := listAll(t, repo, domain.OrderListQuery{
+			Filter: domain.OrderFilter{CustomerID: customer},
+			Sort:   tt.sort,
+		}, 0)
+		assertIDs(t, tt.sort.String(), got, tt.want)
+	}
+}
+
+func testListPagination(t *testing.T, repo repository.OrderRepository) {
+	customer := newCustomer()
+
+	var orders []*domain.Order
+	for range 7 {
+		order := newOrder(t, customer, domain.LineItem{Item: "apple", Quantity: 1})
+		mustCreate(t, repo, order)
+		orders = append(orders, order)
+	}
+	slices.SortFunc(orders, func(a, b *domain.Order) int { return slices.Compare(a.ID[:], b.ID[:]) })
+
+	for _, pageSize := range []int{1, 3, 7, 10} {
+		got := listAll(t, repo, domain.OrderListQuery{
+			Filter: domain.OrderFilter{CustomerID: customer},
+			Sort:   domain.OrderSort{Field: domain.OrderSortByID},
+		}, pageSize)
+		assertIDs(t, fmt.Sprintf("page size %d", pageSize), got, orders)
+	}
+}
+
+func testListFilter(t *testing.T, repo repository.OrderRepository) {
+	ctx := t.Context()
+	customer := newCustomer()
+
+	small := newOrder(t, customer, domain.LineItem{Item: "apple", Quantity: 1})
+	large := newOrder(t, customer, domain.LineItem{Item: "pear", Quantity: 5}, domain.LineItem{Item: "plum", Quantity: 5})
+	large.CreatedAt = small.CreatedAt.Add(time.Hour)
+	other := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	for _, order := range []*domain.Order{small, large, other} {
+		mustCreate(t, repo, order)
+	}
+
+	confirmed := mustGet(t, repo, large.ID)
+	if err := confirmed.Transition(domain.OrderStatusConfirmed); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Update(ctx, confirmed, domain.AnyCustomer); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	minQuantity, maxQuantity := int32(2), int32(9)
+	tests := []struct {
+		name   string
+		filter domain.OrderFilter
+		want   []*domain.Order
+	}{
+		{"customer", domain.OrderFilter{}, []*domain.Order{small, large}},
+		{"item", domain.OrderFilter{Item: "plum"}, []*domain.Order{large}},
+		{"min quantity", domain.OrderFilter{MinQuantity: &minQuantity}, []*domain.Order{large}},
+		{"max quantity", domain.OrderFilter{MaxQuantity: &maxQuantity}, []*domain.Order{small}},
+		{"status", domain.OrderFilter{Statuses: []domain.OrderStatus{domain.OrderStatusConfirmed}}, []*domain.Order{large}},
+		{"created after", domain.OrderFilter{CreatedAfter: large.CreatedAt}, []*domain.Order{large}},
+		{"created before", domain.OrderFilter{CreatedBefore: large.CreatedAt}, []*domain.Order{small}},
+	}
+	for _, tt := range tests {
+		tt.filter.CustomerID = customer
+		got := listAll(t, repo, domain.OrderListQuery{Filter: tt.filter}, 0)
+		assertIDs(t, tt.name, got, tt.want)
+	}
+}
+
+func testListInvalidPageToken(t *testing.T, repo repository.OrderRepository) {
+	customer := newCustomer()
+	for range 2 {
+		mustCreate(t, repo, newOrder(t, customer, domain.LineItem{Item: "apple", Quantity: 1}))
+	}
+
+	query := domain.OrderListQuery{PageSize: 1, Filter: domain.OrderFilter{CustomerID: customer}}
+	page, err := repo.List(t.Context(), query)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"garbage":      "not a token",
+		"other filter": page.NextPageToken,
+	} {
+		query := domain.OrderListQuery{PageToken: token, Filter: domain.OrderFilter{CustomerID: newCustomer()}}
+		if _, err := repo.List(t.Context(), query); !errors.Is(err, domain.ErrInvalidPageToken) {
+			t.Errorf("List(%s token) = %v, want %v", name, err, domain.ErrInvalidPageToken)
+		}
+	}
+}
+
+func testNoAliasing(t *testing.T, repo repository.OrderRepository) {
+	ctx := t.Context()
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	want := order.Clone()
+	mustCreate(t, repo, order)
+
+	order.Status = domain.OrderStatusCancelled
+	order.Items[0].Quantity = 99
+
+	got := mustGet(t, repo, want.ID)
+	got.Status = domain.OrderStatusCancelled
+	got.Items[0].Item = "mutated"
+
+	page, err := repo.List(ctx, domain.OrderListQuery{Filter: domain.OrderFilter{CustomerID: want.CustomerID}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	for _, listed := range page.Orders {
+		listed.Items = nil
+	}
+
+	assertOrder(t, mustGet(t, repo, want.ID), want)
+}
+
+func testContextCanceled(t *testing.T, repo repository.OrderRepository) {
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	unsaved := newOrder(t, order.CustomerID, domain.LineItem{Item: "pear", Quantity: 1})
+	update := order.Clone()
+	update.Status = domain.OrderStatusConfirmed
+	calls := map[string]func() error{
+		"Create": func() error { return repo.Create(ctx, unsaved) },
+		"Get": func() error {
+			_, err := repo.Get(ctx, order.ID, domain.AnyCustomer)
+			return err
+		},
+		"Update": func() error { return repo.Update(ctx, update, domain.AnyCustomer) },
+		"Delete": func() error { return repo.Delete(ctx, order.ID, domain.AnyCustomer, domain.AnyVersion) },
+		"List": func() error {
+			_, err := repo.List(ctx, domain.OrderListQuery{Filter: domain.OrderFilter{CustomerID: order.CustomerID}})
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s(canceled) = %v, want %v", name, err, context.Canceled)
+		}
+	}
+
+	assertOrder(t, mustGet(t, repo, order.ID), order)
+	if _, err := repo.Get(t.Context(), unsaved.ID, domain.AnyCustomer); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Errorf("Get(canceled create) = %v, want %v", err, domain.ErrOrderNotFound)
+	}
+}
+
+func testConcurrentCreates(t *testing.T, repo repository.OrderRepository) {
+	customer := newCustomer()
+
+	orders := make([]*domain.Order, concurrentWriters)
+	for i := range orders {
+		orders[i] = newOrder(t, customer, domain.LineItem{Item: "apple", Quantity: int32(i + 1)}) //nolint:gosec // small
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(orders))
+	for _, order := range orders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.Create(t.Context(), order)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent Create: %v", err)
+		}
+	}
+
+	slices.SortFunc(orders, func(a, b *domain.Order) int { return slices.Compare(a.ID[:], b.ID[:]) })
+	got := listAll(t, repo, domain.OrderListQuery{
+		Filter: domain.OrderFilter{CustomerID: customer},
+		Sort:   domain.OrderSort{Field: domain.OrderSortByID},
+	}, 0)
+	assertIDs(t, "concurrent creates", got, orders)
+}
+
+func testConcurrentUpdates(t *testing.T, repo repository.OrderRepository) {
+	order := newOrder(t, newCustomer(), domain.LineItem{Item: "apple", Quantity: 1})
+	mustCreate(t, repo, order)
+
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int32
+	)
+	for i := range concurrentWriters {
+		update := order.Clone()
+		update.Items = []domain.LineItem{{Item: fmt.Sprintf("writer-%d", i), Quantity: 1}}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := repo.Update(t.Context(), update, domain.AnyCustomer)
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case !errors.Is(err, domain.ErrVersionMismatch):
+				t.Errorf("concurrent Update = %v, want nil or %v", err, domain.ErrVersionMismatch)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := succeeded.Load(); n != 1 {
+		t.Fatalf("%d concurrent updates of version %d succeeded, want 1", n, order.Version)
+	}
+	if got := mustGet(t, repo, order.ID); got.Version != order.Version+1 {
+		t.Fatalf("version after concurrent updates = %d, want %d", got.Version, order.Version+1)
+	}
+}
+
+func newCustomer() string {
+	return "customer-" + uuid.NewString()
+}
+
+func newOrder(t *testing.T, customerID string, items ...domain.LineItem) *domain.Order {
+	t.Helper()
+
+	order, err := domain.NewOrder(uuid.New(), customerID, items)
+	if err != nil {
+		t.Fatalf("NewOrder: %v", err)
+	}
+
+	return order
+}
+
+func mustCreate(t *testing.T, repo repository.OrderRepository, order *domain.Order) {
+	t.Helper()
+
+	if err := repo.Create(t.Context(), order); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+func mustGet(t *testing.T, repo repository.OrderRepository, id uuid.UUID) *domain.Order {
+	t.Helper()
+
+	order, err := repo.Get(t.Context(), id, domain.AnyCustomer)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+
+	return order
+}
+
+// listAll follows page tokens until the last page.
+func listAll(t *testing.T, repo repository.OrderRepository, query domain.OrderListQuery, pageSize int) []*domain.Order {
+	t.Helper()
+
+	query.PageSize = pageSize
+
+	var orders []*domain.Order
+	for {
+		page, err := repo.List(t.Context(), query)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if pageSize > 0 && len(page.Orders) > pageSize {
+			t.Fatalf("List returned %d orders for page size %d", len(page.Orders), pageSize)
+		}
+		orders = append(orders, page.Orders...)
+
+		if page.NextPageToken == "" {
+			return orders
+		}
+		query.PageToken = page.NextPageToken
+	}
+}
+
+func assertOrder(t *testing.T, got, want *domain.Order) {
+	t.Helper()
+
+	if got.ID != want.ID || got.CustomerID != want.CustomerID || got.Status != want.Status ||
+		!got.CreatedAt.Equal(want.CreatedAt) || got.Version != want.Version || !slices.Equal(got.Items, want.Items) {
+		t.Fatalf("got order %+v, want %+v", got, want)
+	}
+}
+
+func assertIDs(t *testing.T, name string, got, want []*domain.Order) {
+	t.Helper()
+
+	ids := func(orders []*domain.Order) []uuid.UUID {
+		out := make([]uuid.UUID, 0, len(orders))
+		for _, order := range orders {
+			out = append(out, order.ID)
+		}
+		return out
+	}
+
+	if gotIDs, wantIDs := ids(got), ids(want); !slices.Equal(gotIDs, wantIDs) {
+		t.Errorf("%s: got orders %v, want %v", name, gotIDs, wantIDs)
+	}
+}
+
+func reversed(orders []*domain.Order) []*domain.Order {
+	out := slices.Clone(orders)
+	slices.Reverse(out)
+
+	return out
+}
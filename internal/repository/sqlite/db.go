@@ -0,0 +1,84 @@
+// Package sqlite stores orders in an embedded SQLite database for
+// deployments that can't run Postgres.
+//
+// The schema follows cmd/migrate/migrations version for version, except
+// for the Postgres-only outbox (000006, 000007, 000009): order events are
+// published in process, so Watch only sees changes made through the same
+// server.
+package sqlite
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	driverName = "sqlite"
+
+	busyTimeoutMillis = 5000
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Open opens the database file at path in WAL mode and applies pending
+// migrations. Write transactions take the lock up front so that concurrent
+// writers wait for each other instead of failing with SQLITE_BUSY.
+func Open(ctx context.Context, path string) (*sqlx.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "synchronous(NORMAL)")
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeoutMillis))
+	params.Set("_txlock", "immediate")
+
+	db, err := sqlx.Open(driverName, "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+
+	if err := migrateUp(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateUp(db *sqlx.DB) error {
+	drv, err := migratesqlite.WithInstance(db.DB, &migratesqlite.Config{})
+	if err != nil {
+		return fmt.Errorf("sqlite migration driver: %w", err)
+	}
+
+	src, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		return fmt.Errorf("migration source: %w", err)
+	}
+	defer src.Close()
+
+	// Closing m would close db as well, so only the source is closed.
+	m, err := migrate.NewWithInstance("iofs", src, driverName, drv)
+	if err != nil {
+		return fmt.Errorf("init migrations: %w", err)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+
+	return nil
+}
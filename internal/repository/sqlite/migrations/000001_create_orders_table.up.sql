@@ -0,0 +1,5 @@
+create table if not exists orders (
+    id text primary key,
+    item text not null check (length(item) <= 500),
+    quantity integer not null check (quantity > 0)
+);
@@ -0,0 +1,3 @@
+alter table orders
+    add column status text not null default 'pending'
+    check (status in ('pending', 'confirmed', 'paid', 'shipped', 'delivered', 'cancelled'));
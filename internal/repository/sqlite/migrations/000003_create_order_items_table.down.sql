@@ -0,0 +1,9 @@
+alter table orders add column item text not null default '' check (length(item) <= 500);
+alter table orders add column quantity integer not null default 1 check (quantity > 0);
+
+update orders
+set item = i.item, quantity = i.quantity
+from order_items i
+where i.order_id = orders.id and i.position = 0;
+
+drop table if exists order_items;
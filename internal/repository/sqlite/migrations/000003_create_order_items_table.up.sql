@@ -0,0 +1,14 @@
+create table if not exists order_items (
+    order_id text not null references orders (id) on delete cascade,
+    position integer not null check (position >= 0),
+    item text not null check (length(item) <= 500),
+    quantity integer not null check (quantity > 0),
+    primary key (order_id, position)
+);
+
+insert into order_items (order_id, position, item, quantity)
+select id, 0, item, quantity
+from orders;
+
+alter table orders drop column item;
+alter table orders drop column quantity;
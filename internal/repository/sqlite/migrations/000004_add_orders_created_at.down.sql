@@ -0,0 +1,5 @@
+drop index if exists order_items_item_idx;
+drop index if exists orders_status_idx;
+drop index if exists orders_created_at_id_idx;
+
+alter table orders drop column created_at;
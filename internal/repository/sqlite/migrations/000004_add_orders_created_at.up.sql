@@ -0,0 +1,9 @@
+-- created_at holds fixed-width UTC timestamps (2006-01-02T15:04:05.000000Z)
+-- so that text comparison orders them chronologically. SQLite only accepts
+-- constant defaults when adding a column.
+alter table orders
+    add column created_at text not null default '1970-01-01T00:00:00.000000Z';
+
+create index if not exists orders_created_at_id_idx on orders (created_at, id);
+create index if not exists orders_status_idx on orders (status);
+create index if not exists order_items_item_idx on order_items (item);
@@ -0,0 +1,2 @@
+alter table orders
+    add column version integer not null default 1 check (version > 0);
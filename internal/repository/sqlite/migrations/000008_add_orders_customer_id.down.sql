@@ -0,0 +1,3 @@
+drop index if exists orders_customer_id_created_at_idx;
+
+alter table orders drop column customer_id;
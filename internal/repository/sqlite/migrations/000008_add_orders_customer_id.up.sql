@@ -0,0 +1,4 @@
+alter table orders
+    add column customer_id text not null default '';
+
+create index if not exists orders_customer_id_created_at_idx on orders (customer_id, created_at, id);
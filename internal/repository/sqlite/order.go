@@ -0,0 +1,321 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"orderservice/internal/domain"
+	"orderservice/internal/metrics"
+	"orderservice/internal/repository"
+	"orderservice/internal/repository/inmemory"
+	"orderservice/internal/repository/sqlorder"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	tracerName = "orderservice/internal/repository/sqlite"
+
+	// timeLayout is fixed-width so that stored timestamps sort as text.
+	timeLayout = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+var dialect = sqlorder.Dialect{
+	Placeholder: func(n int) string { return "?" + strconv.Itoa(n) },
+	EncodeTime:  func(t time.Time) any { return formatTime(t) },
+}
+
+type OrderRepository struct {
+	db       *sqlx.DB
+	events   *inmemory.EventBroadcaster
+	observer sqlorder.Observer
+}
+
+type orderRow struct {
+	ID         uuid.UUID          `db:"id"`
+	CustomerID string             `db:"customer_id"`
+	Status     domain.OrderStatus `db:"status"`
+	CreatedAt  string             `db:"created_at"`
+	Version    int64              `db:"version"`
+}
+
+type Config struct {
+	Metrics *metrics.Metrics
+}
+
+func NewOrderRepository(db *sqlx.DB, config *Config) *OrderRepository {
+	if config == nil {
+		config = &Config{}
+	}
+
+	return &OrderRepository{
+		db:       db,
+		events:   inmemory.NewEventBroadcaster(),
+		observer: sqlorder.Observer{TracerName: tracerName, Metrics: config.Metrics},
+	}
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	err := r.observer.Observe(ctx, "create", func(ctx context.Context) error { return r.insert(ctx, order) })
+	if err != nil {
+		return err
+	}
+
+	r.events.Publish(domain.NewOrderEvent(domain.OrderEventCreated, order))
+	return nil
+}
+
+func (r *OrderRepository) insert(ctx context.Context, order *domain.Order) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const query = `
+		insert into orders (id, customer_id, status, created_at, version)
+		values (?1, ?2, ?3, ?4, ?5)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		order.ID, order.CustomerID, order.Status, formatTime(order.CreatedAt), order.Version)
+	if err != nil {
+		if isPrimaryKeyViolation(err) {
+			return domain.ErrOrderAlreadyExist
+		}
+		return fmt.Errorf("create order: %w", err)
+	}
+
+	if err := sqlorder.InsertItems(ctx, dialect, tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) Get(ctx context.Context, id uuid.UUID, customerID string) (*domain.Order, error) {
+	var order *domain.Order
+	err := r.observer.Observe(ctx, "get", func(ctx context.Context) error {
+		var err error
+		order, err = r.selectByID(ctx, id, customerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) selectByID(ctx context.Context, id uuid.UUID, customerID string) (*domain.Order, error) {
+	const query = `
+		select id, customer_id, status, created_at, version
+		from orders
+		where id = ?1 and (?2 = '' or customer_id = ?2)
+	`
+
+	var row orderRow
+	if err := r.db.GetContext(ctx, &row, query, id, customerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("get order by id: %w", err)
+	}
+
+	order, err := row.order()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sqlorder.LoadItems(ctx, dialect, r.db, []*domain.Order{order}); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) Update(ctx context.Context, order *domain.Order, customerID string) error {
+	err := r.observer.Observe(ctx, "update", func(ctx context.Context) error { return r.update(ctx, order, customerID) })
+	if err != nil {
+		return err
+	}
+
+	r.events.Publish(domain.NewOrderEvent(domain.OrderEventUpdated, order))
+	return nil
+}
+
+func (r *OrderRepository) update(ctx context.Context, order *domain.Order, customerID string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const query = `
+		update orders
+		set status = ?1, version = version + 1
+		where id = ?2 and version = ?3 and (?4 = '' or customer_id = ?4)
+		returning customer_id
+	`
+
+	// Ownership never changes; hand the stored owner back the way the
+	// in-memory repository does.
+	var owner string
+	err = tx.GetContext(ctx, &owner, query, order.Status, order.ID, order.Version, customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sqlorder.ConflictError(ctx, dialect, tx, order.ID, customerID)
+		}
+		return fmt.Errorf("update order: %w", err)
+	}
+
+	if _, err := sqlorder.DeleteItems(ctx, dialect, tx, order.ID); err != nil {
+		return err
+	}
+	if err := sqlorder.InsertItems(ctx, dialect, tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	order.CustomerID = owner
+	order.Version++
+
+	return nil
+}
+
+func (r *OrderRepository) Delete(ctx context.Context, id uuid.UUID, customerID string, version int64) error {
+	var order *domain.Order
+	err := r.observer.Observe(ctx, "delete", func(ctx context.Context) error {
+		var err error
+		order, err = r.delete(ctx, id, customerID, version)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.events.Publish(domain.NewOrderEvent(domain.OrderEventDeleted, order))
+	return nil
+}
+
+func (r *OrderRepository) delete(
+	ctx context.Context,
+	id uuid.UUID,
+	customerID string,
+	version int64,
+) (*domain.Order, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	items, err := sqlorder.DeleteItems(ctx, dialect, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	const query = `
+		delete from orders
+		where id = ?1 and (?2 = 0 or version = ?2) and (?3 = '' or customer_id = ?3)
+		returning id, customer_id, status, created_at, version
+	`
+
+	var row orderRow
+	if err := tx.GetContext(ctx, &row, query, id, version, customerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sqlorder.ConflictError(ctx, dialect, tx, id, customerID)
+		}
+		return nil, fmt.Errorf("delete order: %w", err)
+	}
+
+	order, err := row.order()
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) List(ctx context.Context, query domain.OrderListQuery) (*domain.OrderPage, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	cursor, err := repository.DecodeOrderCursor(query)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlQuery, args := sqlorder.BuildListQuery(dialect, query, cursor)
+
+	var orders []*domain.Order
+	err = r.observer.Observe(ctx, "list", func(ctx context.Context) error {
+		var rows []orderRow
+		if err := r.db.SelectContext(ctx, &rows, sqlQuery, args...); err != nil {
+			return fmt.Errorf("list orders: %w", err)
+		}
+
+		orders = make([]*domain.Order, 0, len(rows))
+		for _, row := range rows {
+			order, err := row.order()
+			if err != nil {
+				return err
+			}
+			orders = append(orders, order)
+		}
+
+		return sqlorder.LoadItems(ctx, dialect, r.db, orders)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.NewOrderPage(query, orders), nil
+}
+
+func (r *OrderRepository) Watch(ctx context.Context, after int64, fn func(domain.OrderEvent) error) error {
+	return r.events.Watch(ctx, after, fn)
+}
+
+func (row orderRow) order() (*domain.Order, error) {
+	createdAt, err := time.Parse(timeLayout, row.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("parse created_at of order %s: %w", row.ID, err)
+	}
+
+	return &domain.Order{
+		ID:         row.ID,
+		CustomerID: row.CustomerID,
+		Status:     row.Status,
+		CreatedAt:  createdAt,
+		Version:    row.Version,
+	}, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func isPrimaryKeyViolation(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
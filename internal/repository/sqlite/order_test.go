@@ -0,0 +1,22 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"orderservice/internal/repository"
+	"orderservice/internal/repository/repositorytest"
+	"orderservice/internal/repository/sqlite"
+)
+
+func TestOrderRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.OrderRepository {
+		db, err := sqlite.Open(t.Context(), filepath.Join(t.TempDir(), "orders.db"))
+		if err != nil {
+			t.Fatalf("open database: %v", err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+
+		return sqlite.NewOrderRepository(db, nil)
+	})
+}
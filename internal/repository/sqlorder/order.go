@@ -0,0 +1,169 @@
+package sqlorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"orderservice/internal/domain"
+	"orderservice/internal/metrics"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type itemRow struct {
+	OrderID  uuid.UUID `db:"order_id"`
+	Position int       `db:"position"`
+	Item     string    `db:"item"`
+	Quantity int32     `db:"quantity"`
+}
+
+// Observer traces database operations and records their latency and
+// outcome.
+type Observer struct {
+	TracerName string
+	Metrics    *metrics.Metrics
+}
+
+// Observe runs a database operation inside its own span.
+func (o Observer) Observe(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	ctx, span := otel.Tracer(o.TracerName).Start(ctx, "OrderRepository."+operation,
+		trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	start := time.Now()
+	err := fn(ctx)
+
+	var outcome string
+	switch {
+	case err == nil:
+		outcome = "ok"
+	case errors.Is(err, domain.ErrOrderNotFound):
+		outcome = "not_found"
+	case errors.Is(err, domain.ErrVersionMismatch), errors.Is(err, domain.ErrOrderAlreadyExist):
+		outcome = "conflict"
+	default:
+		outcome = "error"
+	}
+	o.Metrics.ObserveDBQuery(operation, outcome, time.Since(start))
+
+	if outcome == "error" {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// ConflictError explains why a versioned write matched no rows.
+func ConflictError(ctx context.Context, d Dialect, tx *sqlx.Tx, id uuid.UUID, customerID string) error {
+	query := d.Bind(`
+		select exists (select 1 from orders where id = $1 and ($2 = '' or customer_id = $2))
+	`)
+
+	var exists bool
+	if err := tx.GetContext(ctx, &exists, query, id, customerID); err != nil {
+		return fmt.Errorf("check order existence: %w", err)
+	}
+
+	if exists {
+		return domain.ErrVersionMismatch
+	}
+	return domain.ErrOrderNotFound
+}
+
+func InsertItems(ctx context.Context, d Dialect, tx *sqlx.Tx, order *domain.Order) error {
+	if len(order.Items) == 0 {
+		return nil
+	}
+
+	const fieldsPerItem = 4
+	values := make([]string, 0, len(order.Items))
+	args := make([]any, 0, len(order.Items)*fieldsPerItem)
+	for i, item := range order.Items {
+		placeholders := make([]string, 0, fieldsPerItem)
+		for _, v := range []any{order.ID, i, item.Item, item.Quantity} {
+			args = append(args, v)
+			placeholders = append(placeholders, d.Placeholder(len(args)))
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+	}
+
+	query := "insert into order_items (order_id, position, item, quantity) values " + strings.Join(values, ", ")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("create order items: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteItems deletes the line items of an order and returns them in order.
+func DeleteItems(ctx context.Context, d Dialect, tx *sqlx.Tx, orderID uuid.UUID) ([]domain.LineItem, error) {
+	query := d.Bind(`
+		delete from order_items
+		where order_id = $1
+		returning order_id, position, item, quantity
+	`)
+
+	var rows []itemRow
+	if err := tx.SelectContext(ctx, &rows, query, orderID); err != nil {
+		return nil, fmt.Errorf("delete order items: %w", err)
+	}
+
+	slices.SortFunc(rows, func(a, b itemRow) int { return a.Position - b.Position })
+
+	items := make([]domain.LineItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, domain.LineItem{
+			Item:     row.Item,
+			Quantity: row.Quantity,
+		})
+	}
+
+	return items, nil
+}
+
+// LoadItems fills in the line items of orders with a single query.
+func LoadItems(ctx context.Context, d Dialect, db *sqlx.DB, orders []*domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(orders))
+	args := make([]any, 0, len(orders))
+	byID := make(map[uuid.UUID]*domain.Order, len(orders))
+	for _, order := range orders {
+		args = append(args, order.ID)
+		placeholders = append(placeholders, d.Placeholder(len(args)))
+		byID[order.ID] = order
+	}
+
+	query := `
+		select order_id, position, item, quantity
+		from order_items
+		where order_id in (` + strings.Join(placeholders, ", ") + `)
+		order by order_id, position
+	`
+
+	var rows []itemRow
+	if err := db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return fmt.Errorf("list order items: %w", err)
+	}
+
+	for _, row := range rows {
+		order := byID[row.OrderID]
+		order.Items = append(order.Items, domain.LineItem{
+			Item:     row.Item,
+			Quantity: row.Quantity,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+// Package sqlorder holds the queries and helpers shared by the SQL order
+// repositories, so that the postgres and sqlite backends can't drift apart.
+// What differs between the databases is captured by a Dialect.
+package sqlorder
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"orderservice/internal/domain"
+	"orderservice/internal/repository"
+)
+
+const TotalQuantityExpr = "(select coalesce(sum(i.quantity), 0) from order_items i where i.order_id = o.id)"
+
+type Dialect struct {
+	// Placeholder renders the bind parameter with the 1-based index n.
+	Placeholder func(n int) string
+	// EncodeTime converts a timestamp to the value stored in created_at.
+	EncodeTime func(t time.Time) any
+}
+
+// Bind renders query, written with $1-style placeholders, in the dialect's
+// placeholder style.
+func (d Dialect) Bind(query string) string {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(query, '$')
+		if i < 0 {
+			b.WriteString(query)
+			return b.String()
+		}
+
+		j := i + 1
+		for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+			j++
+		}
+
+		b.WriteString(query[:i])
+		n, err := strconv.Atoi(query[i+1 : j])
+		if err != nil {
+			b.WriteByte('$')
+		} else {
+			b.WriteString(d.Placeholder(n))
+		}
+		query = query[j:]
+	}
+}
+
+// BuildListQuery selects the page of orders after cursor, fetching one row
+// more than the page size to tell whether another page follows.
+func BuildListQuery(d Dialect, query domain.OrderListQuery, cursor *repository.OrderCursor) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return d.Placeholder(len(args))
+	}
+
+	filter := query.Filter
+	if filter.CustomerID != domain.AnyCustomer {
+		conds = append(conds, "o.customer_id = "+arg(filter.CustomerID))
+	}
+	if filter.Item != "" {
+		conds = append(conds, "exists (select 1 from order_items i where i.order_id = o.id and i.item = "+
+			arg(filter.Item)+")")
+	}
+	if filter.MinQuantity != nil {
+		conds = append(conds, TotalQuantityExpr+" >= "+arg(*filter.MinQuantity))
+	}
+	if filter.MaxQuantity != nil {
+		conds = append(conds, TotalQuantityExpr+" <= "+arg(*filter.MaxQuantity))
+	}
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			placeholders = append(placeholders, arg(status.String()))
+		}
+		conds = append(conds, "o.status in ("+strings.Join(placeholders, ", ")+")")
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conds = append(conds, "o.created_at >= "+arg(d.EncodeTime(filter.CreatedAfter)))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conds = append(conds, "o.created_at < "+arg(d.EncodeTime(filter.CreatedBefore)))
+	}
+
+	direction, cmp := "asc", ">"
+	if query.Sort.Desc {
+		direction, cmp = "desc", "<"
+	}
+
+	orderBy := "o.id " + direction
+	if cursor != nil && query.Sort.Field == domain.OrderSortByID {
+		conds = append(conds, "o.id "+cmp+" "+arg(cursor.ID))
+	}
+	if query.Sort.Field == domain.OrderSortByCreateTime {
+		orderBy = "o.created_at " + direction + ", o.id " + direction
+		if cursor != nil {
+			conds = append(conds, "(o.created_at, o.id) "+cmp+" ("+arg(d.EncodeTime(cursor.CreatedAt))+", "+
+				arg(cursor.ID)+")")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`
+		select o.id, o.customer_id, o.status, o.created_at, o.version
+		from orders o`)
+	if len(conds) > 0 {
+		b.WriteString("\n\t\twhere " + strings.Join(conds, "\n\t\tand "))
+	}
+	b.WriteString("\n\t\torder by " + orderBy)
+	b.WriteString("\n\t\tlimit " + arg(query.PageSize+1) + "\n")
+
+	return b.String(), args
+}
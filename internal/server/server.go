@@ -2,28 +2,50 @@ package server
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
+	"net/textproto"
+	"sync"
 	"time"
 
+	"orderservice/internal/auth"
 	"orderservice/internal/config"
+	"orderservice/internal/health"
 	"orderservice/internal/interceptor"
+	"orderservice/internal/metrics"
+	"orderservice/internal/outbox"
+	"orderservice/internal/ratelimit"
+	"orderservice/internal/tlsconfig"
+	"orderservice/internal/tracing"
 
 	grpcHandlers "orderservice/internal/handler/grpc"
 	httpHandlers "orderservice/internal/handler/http"
+	"orderservice/internal/repository"
+	inmemoryRepo "orderservice/internal/repository/inmemory"
 	orderPostgresRepo "orderservice/internal/repository/postgres"
+	redisRepo "orderservice/internal/repository/redis"
 	"orderservice/internal/service"
 
 	pb "orderservice/pkg/api/order"
 
+	"github.com/XSAM/otelsql"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // postgres driver
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -40,56 +62,234 @@ const (
 )
 
 type Server struct {
-	grpcServer *grpc.Server
-	config     *config.Config
-	db         *sqlx.DB
-	redisDB    *redis.Client
+	grpcServer   *grpc.Server
+	config       *config.Config
+	metrics      *metrics.Metrics
+	tls          *tlsconfig.Reloader
+	rateLimiter  *ratelimit.Limiter
+	health       *health.Checker
+	db           *sqlx.DB
+	redisDB      *redis.Client
+	outboxRelay  *outbox.Relay
+	orderWatcher *orderPostgresRepo.OrderWatcher
+	// cacheSubscriber evicts orders changed by other replicas from the
+	// in-process cache tier.
+	cacheSubscriber func(ctx context.Context)
+
+	mu           sync.Mutex
+	httpServer   *http.Server
+	httpListener net.Listener
+	stopWorkers  context.CancelFunc
+	stopGateway  context.CancelFunc
+	workers      sync.WaitGroup
 }
 
-func New(cfg *config.Config) *Server {
-	loggerInterceptor := interceptor.NewLoggerInterceptor()
+func New(cfg *config.Config) (*Server, error) {
+	serverMetrics := metrics.New()
+	recoveryInterceptor := interceptor.NewRecoveryInterceptor(slog.Default(), serverMetrics)
+	loggerInterceptor := interceptor.NewLoggerInterceptor(slog.Default())
+	metricsInterceptor := interceptor.NewMetricsInterceptor(serverMetrics)
 
-	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(loggerInterceptor.Unary()),
-		grpc.StreamInterceptor(loggerInterceptor.Stream()),
-	)
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		recoveryInterceptor.Unary(),
+		loggerInterceptor.Unary(),
+		metricsInterceptor.Unary(),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		recoveryInterceptor.Stream(),
+		loggerInterceptor.Stream(),
+		metricsInterceptor.Stream(),
+	}
+
+	if cfg.AuthEnable {
+		authenticator, err := newAuthenticator(*cfg)
+		if err != nil {
+			return nil, fmt.Errorf("set up authentication: %w", err)
+		}
+
+		authInterceptor := interceptor.NewAuthInterceptor(authenticator, grpcHandlers.MethodScopes())
+		unaryInterceptors = append(unaryInterceptors, authInterceptor.Unary())
+		streamInterceptors = append(streamInterceptors, authInterceptor.Stream())
+	}
+
+	var rateLimiter *ratelimit.Limiter
+	if cfg.RateLimitEnable {
+		rateLimitInterceptor, limiter, err := newRateLimitInterceptor(*cfg)
+		if err != nil {
+			return nil, fmt.Errorf("set up rate limiting: %w", err)
+		}
+
+		rateLimiter = limiter
+		unaryInterceptors = append(unaryInterceptors, rateLimitInterceptor.Unary())
+		streamInterceptors = append(streamInterceptors, rateLimitInterceptor.Stream())
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+
+	var reloader *tlsconfig.Reloader
+	if cfg.TLSEnable {
+		var err error
+		reloader, err = tlsconfig.NewReloader(&tlsconfig.Config{
+			CertFile:       cfg.TLSCertFile,
+			KeyFile:        cfg.TLSKeyFile,
+			CAFile:         cfg.TLSCAFile,
+			ClientAuth:     cfg.TLSClientAuth,
+			ServerName:     cfg.TLSServerName,
+			ReloadInterval: cfg.TLSReloadInterval,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("set up TLS: %w", err)
+		}
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	healthServices := []string{pb.OrderService_ServiceDesc.ServiceName}
+	healthChecker := health.NewChecker(healthServer, healthServices, cfg.HealthProbeInterval)
 
 	return &Server{
-		grpcServer: grpcServer,
-		config:     cfg,
+		grpcServer:  grpcServer,
+		config:      cfg,
+		health:      healthChecker,
+		metrics:     serverMetrics,
+		tls:         reloader,
+		rateLimiter: rateLimiter,
+	}, nil
+}
+
+func newRateLimitInterceptor(cfg config.Config) (*interceptor.RateLimitInterceptor, *ratelimit.Limiter, error) {
+	limits, err := ratelimit.ParseLimits(cfg.RateLimits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var defaultLimit *ratelimit.Limit
+	if cfg.RateLimitDefault != "" {
+		limit, err := ratelimit.ParseLimit(cfg.RateLimitDefault)
+		if err != nil {
+			return nil, nil, err
+		}
+		defaultLimit = &limit
 	}
+
+	limiter := ratelimit.NewLimiter()
+
+	return interceptor.NewRateLimitInterceptor(limiter, limits, defaultLimit), limiter, nil
 }
 
-func runHTTPHandler(s *Server, grpcServerEndpoint *string) error {
-	ctx := context.Background()
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+func newAuthenticator(cfg config.Config) (*auth.Authenticator, error) {
+	var (
+		jwks *auth.JWKS
+		err  error
+	)
+	switch {
+	case cfg.AuthJWKSFile != "":
+		jwks, err = auth.LoadJWKSFile(cfg.AuthJWKSFile)
+	case cfg.AuthJWKSURL != "":
+		jwks, err = auth.LoadJWKSURL(context.Background(), cfg.AuthJWKSURL)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	gwmux := runtime.NewServeMux()
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-	err := pb.RegisterOrderServiceHandlerFromEndpoint(ctx, gwmux, *grpcServerEndpoint, opts)
+	return auth.NewAuthenticator(&auth.Config{
+		HS256Secret: cfg.AuthHS256Secret,
+		JWKS:        jwks,
+		Issuer:      cfg.AuthIssuer,
+		Audience:    cfg.AuthAudience,
+	})
+}
+
+func gatewayHeaderMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case "Idempotency-Key":
+		return grpcHandlers.IdempotencyKeyMetadata, true
+	case "Last-Event-Id":
+		return grpcHandlers.LastEventIDMetadata, true
+	case "X-Request-Id":
+		return interceptor.RequestIDMetadata, true
+	case "Authorization":
+		// runtime forwards Authorization as-is to the "authorization" key the
+		// auth interceptor reads; skip the grpcgateway- prefixed duplicate.
+		return "", false
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+func (s *Server) newHTTPServer(ctx context.Context, grpcServerEndpoint string) (*http.Server, error) {
+	gwmux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+		runtime.WithMarshalerOption(httpHandlers.EventStreamContentType, httpHandlers.NewSSEMarshaler()),
+		runtime.WithErrorHandler(httpHandlers.ErrorHandler),
+		runtime.WithForwardResponseRewriter(httpHandlers.RewriteErrorResponse),
+	)
+	transportCreds := insecure.NewCredentials()
+	if s.tls != nil {
+		transportCreds = credentials.NewTLS(s.tls.ClientConfig())
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCreds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	err := pb.RegisterOrderServiceHandlerFromEndpoint(ctx, gwmux, grpcServerEndpoint, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	mux := http.NewServeMux()
-	mux.Handle("/healthz", httpHandlers.NewHealthHandler(s.db, s.redisDB))
-	mux.Handle("/", gwmux)
+	mux.Handle("/healthz", httpHandlers.NewHealthHandler(s.config.StorageBackend, s.db, s.redisDB, s.usesRedis()))
+	mux.Handle("/livez", httpHandlers.NewLivenessHandler())
+	mux.Handle("/readyz", httpHandlers.NewReadinessHandler(s.health))
+	mux.Handle("/metrics", s.metrics.Handler())
+
+	var api http.Handler = httpHandlers.StreamingDeadlineMiddleware(gwmux)
+	if s.tls != nil && s.config.TLSClientAuth {
+		api = httpHandlers.RequireClientCertMiddleware(api)
+	}
+	mux.Handle("/", api)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", s.config.HTTPPort),
-		Handler:      mux,
+		Handler:      otelhttp.NewHandler(mux, "gateway"),
 		ReadTimeout:  httpReadTimeout,
 		WriteTimeout: httpWriteTimeout,
 		IdleTimeout:  httpIdleTimeout,
 	}
+	if s.tls != nil {
+		srv.TLSConfig = s.tls.HTTPServerConfig()
+	}
 
-	return srv.ListenAndServe()
+	return srv, nil
 }
 
 func getDatabase(cfg config.Config) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", cfg.BuildPostgresConnStr())
+	sqlDB, err := otelsql.Open("postgres", cfg.BuildPostgresConnStr(),
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return tracing.HasParent(ctx)
+			},
+		}),
+	)
 	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	db := sqlx.NewDb(sqlDB, "postgres")
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("connect to database: %w", err)
 	}
 
@@ -97,81 +297,279 @@ func getDatabase(cfg config.Config) (*sqlx.DB, error) {
 }
 
 func getRedis(cfg config.Config) (*redis.Client, error) {
-	conn, err := redis.ParseURL(cfg.RedisURI)
-	client := redis.NewClient(&redis.Options{
-		Addr:               conn.Addr,
-		MaxRetries:         redisRetryCount,
-		MinRetryBackoff:    redisMinRetryBackoff,
-		MaxRetryBackoff:    redisMaxRetryBackoff,
-		DialTimeout:        redisDialTimeout,
-		DialerRetries:      redisDialerRetries,
-		DialerRetryTimeout: redisDialTimeout,
-		ReadTimeout:        redisTimeout,
-		WriteTimeout:       redisTimeout,
-	})
+	opts, err := redis.ParseURL(cfg.RedisURI)
 	if err != nil {
 		return nil, fmt.Errorf("parse Redis URI: %w", err)
 	}
 
+	opts.MaxRetries = redisRetryCount
+	opts.MinRetryBackoff = redisMinRetryBackoff
+	opts.MaxRetryBackoff = redisMaxRetryBackoff
+	opts.DialTimeout = redisDialTimeout
+	opts.DialerRetries = redisDialerRetries
+	opts.DialerRetryTimeout = redisDialTimeout
+	opts.ReadTimeout = redisTimeout
+	opts.WriteTimeout = redisTimeout
+
+	client := redis.NewClient(opts)
+
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("instrument Redis client: %w", err)
+	}
+
 	_, err = client.Ping(context.Background()).Result()
 	if err != nil {
+		_ = client.Close()
 		return nil, fmt.Errorf("connect to Redis server: %w", err)
 	}
 
 	return client, nil
 }
 
-func (s *Server) RegisterServices() {
-	db, err := getDatabase(*s.config)
+func (s *Server) RegisterServices() error {
+	if err := s.checkStorage(); err != nil {
+		return err
+	}
+
+	db, redisDB, err := s.connectDependencies(context.Background())
 	if err != nil {
-		log.Print(err)
+		return err
 	}
 	s.db = db
+	s.redisDB = redisDB
+
+	if db != nil {
+		s.health.AddProbe(s.config.StorageBackend, db.PingContext)
+	}
+	if redisDB != nil {
+		s.health.AddProbe("redis", func(ctx context.Context) error { return redisDB.Ping(ctx).Err() })
+	}
 
-	redisDB, err := getRedis(*s.config)
+	orderRepo, watcher, err := s.newOrderRepository(db, redisDB)
 	if err != nil {
-		log.Print(err)
+		return err
+	}
+
+	if s.config.OutboxEnable && s.usesPostgres() && redisDB != nil {
+		publisher := outbox.NewRedisStreamPublisher(redisDB, s.config.OutboxStream, int64(s.config.OutboxStreamMaxLen))
+		s.outboxRelay = outbox.NewRelay(db, publisher, &outbox.Config{
+			PollInterval: s.config.OutboxPollInterval,
+			BatchSize:    s.config.OutboxBatchSize,
+			Retention:    s.config.OutboxRetention,
+		})
+	}
+
+	if s.rateLimiter != nil && redisDB != nil {
+		s.rateLimiter.SetShared(ratelimit.NewRedisStore(redisDB))
+	}
+
+	var idempotencyStore repository.IdempotencyStore = inmemoryRepo.NewIdempotencyStore()
+	if redisDB != nil {
+		idempotencyStore = redisRepo.NewIdempotencyStore(redisDB)
 	}
-	s.redisDB = redisDB
 
-	orderRepo := orderPostgresRepo.NewOrderRepository(db, redisDB, &orderPostgresRepo.Config{CacheEnable: true})
-	orderService := service.NewOrderService(orderRepo)
+	orderService := service.NewOrderService(orderRepo, idempotencyStore, watcher, &service.Config{
+		IdempotencyTTL: s.config.IdempotencyTTL,
+	})
 	orderHandler := grpcHandlers.NewOrderHandler(orderService)
 
 	pb.RegisterOrderServiceServer(s.grpcServer, orderHandler)
 
 	if s.config.GRPCEnableReflection {
 		reflection.Register(s.grpcServer)
-		log.Println("gRPC server will start with reflection")
+		slog.Info("gRPC server will start with reflection")
 	}
+
+	return nil
 }
 
+// Start serves gRPC and, when enabled, the HTTP gateway. It blocks until
+// both listeners have stopped and returns the first error either of them
+// reports; stopping through Stop is not an error.
 func (s *Server) Start() error {
+	errCh, listeners, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	for range listeners {
+		if err := <-errCh; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) listen() (<-chan error, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	addr := fmt.Sprintf(":%d", s.config.GRPCPort)
 	lis, err := net.Listen("tcp", addr) //nolint:noctx // no need to use context here
 	if err != nil {
-		return fmt.Errorf("failed to listen: %w", err)
+		return nil, 0, fmt.Errorf("failed to listen: %w", err)
 	}
 
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	s.stopWorkers = stopWorkers
+
 	if s.config.EnableHTTPHandler {
-		go func() {
-			log.Printf("starting HTTP gateway on port %d", s.config.HTTPPort)
-			if err := runHTTPHandler(s, &addr); err != nil {
-				log.Printf("HTTP gateway failed: %v", err)
+		httpLis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.HTTPPort)) //nolint:noctx // see above
+		if err != nil {
+			_ = lis.Close()
+			return nil, 0, fmt.Errorf("failed to listen HTTP: %w", err)
+		}
+
+		// The gateway's connection to the gRPC server lives until the gateway
+		// has drained in Stop, so it doesn't share workersCtx.
+		gatewayCtx, stopGateway := context.WithCancel(context.Background())
+		s.stopGateway = stopGateway
+
+		s.httpServer, err = s.newHTTPServer(gatewayCtx, addr)
+		if err != nil {
+			stopGateway()
+			_ = lis.Close()
+			_ = httpLis.Close()
+			return nil, 0, fmt.Errorf("failed to set up HTTP gateway: %w", err)
+		}
+		s.httpListener = httpLis
+	}
+
+	s.runWorker(func() { s.health.Run(workersCtx) })
+
+	if s.outboxRelay != nil {
+		s.runWorker(func() { s.outboxRelay.Run(workersCtx) })
+		slog.Info("starting outbox relay", slog.String("stream", s.config.OutboxStream))
+	}
+
+	if s.cacheSubscriber != nil {
+		s.runWorker(func() { s.cacheSubscriber(workersCtx) })
+		slog.Info("subscribing to cache invalidations", slog.String("channel", s.config.CacheInvalidation))
+	}
+
+	if s.orderWatcher != nil {
+		s.runWorker(func() {
+			if err := s.orderWatcher.Run(workersCtx); err != nil {
+				slog.Error("order watcher failed", slog.Any("error", err))
 			}
-		}()
+		})
 	}
 
-	log.Printf("starting gRPC server on port %d", s.config.GRPCPort)
+	errCh := make(chan error, 2) //nolint:mnd // one per listener
+	listeners := 1
+
+	if s.httpServer != nil {
+		listeners++
+		slog.Info("starting HTTP gateway", slog.Int("port", s.config.HTTPPort))
 
-	if err := s.grpcServer.Serve(lis); err != nil {
-		return fmt.Errorf("failed to serve: %w", err)
+		go func(srv *http.Server, lis net.Listener) {
+			var err error
+			if srv.TLSConfig != nil {
+				err = srv.ServeTLS(lis, "", "")
+			} else {
+				err = srv.Serve(lis)
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("HTTP gateway failed: %w", err)
+				return
+			}
+			errCh <- nil
+		}(s.httpServer, s.httpListener)
 	}
 
-	return nil
+	slog.Info("starting gRPC server", slog.Int("port", s.config.GRPCPort))
+
+	go func() {
+		if err := s.grpcServer.Serve(lis); err != nil {
+			errCh <- fmt.Errorf("failed to serve: %w", err)
+			return
+		}
+		errCh <- nil
+	}()
+
+	return errCh, listeners, nil
 }
 
-func (s *Server) Stop() {
-	s.grpcServer.GracefulStop()
-	log.Println("gRPC server stopped gracefully")
+func (s *Server) runWorker(fn func()) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		fn()
+	}()
+}
+
+// Stop reports NOT_SERVING, waits for the drain delay, then shuts down the
+// HTTP gateway, the gRPC server, Postgres and Redis in that order. Servers
+// still busy when ctx is done are closed forcibly.
+func (s *Server) Stop(ctx context.Context) error {
+	s.health.Shutdown()
+	if s.config.ShutdownDrainDelay > 0 {
+		slog.Info("draining before shutdown", slog.Duration("delay", s.config.ShutdownDrainDelay))
+		select {
+		case <-time.After(s.config.ShutdownDrainDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+
+	// Stopping the workers first also ends WatchOrders streams, gRPC and SSE
+	// alike, which would otherwise keep both servers waiting until ctx is done.
+	if s.stopWorkers != nil {
+		s.stopWorkers()
+	}
+
+	if s.httpServer != nil {
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shut down HTTP gateway: %w", err))
+			_ = s.httpServer.Close()
+		}
+		slog.Info("HTTP gateway stopped")
+	}
+	if s.stopGateway != nil {
+		s.stopGateway()
+	}
+
+	if err := s.stopGRPC(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	s.workers.Wait()
+
+	if s.db != nil {
+		if err := s.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close postgres: %w", err))
+		}
+	}
+
+	if s.redisDB != nil {
+		if err := s.redisDB.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close redis: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) stopGRPC(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		slog.Info("gRPC server stopped gracefully")
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		<-done
+		return fmt.Errorf("stop gRPC server: %w", ctx.Err())
+	}
 }
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	orderSQLiteRepo "orderservice/internal/repository/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// StartupFailFast aborts startup when any dependency is unreachable.
+	StartupFailFast = "fail_fast"
+	// StartupWait retries unreachable dependencies with backoff until
+	// STARTUP_TIMEOUT elapses.
+	StartupWait = "wait"
+	// StartupDegraded requires the storage backend but starts without Redis,
+	// disabling the cache and falling back to in-process idempotency and rate
+	// limits.
+	StartupDegraded = "degraded"
+
+	startupMinBackoff = 500 * time.Millisecond
+	startupMaxBackoff = 30 * time.Second
+)
+
+var ErrUnknownStartupPolicy = errors.New("unknown startup policy")
+
+func (s *Server) connectDependencies(ctx context.Context) (*sqlx.DB, *redis.Client, error) {
+	switch s.config.StartupPolicy {
+	case StartupFailFast, StartupDegraded:
+	case StartupWait:
+		if s.config.StartupTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.config.StartupTimeout)
+			defer cancel()
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownStartupPolicy, s.config.StartupPolicy)
+	}
+
+	var (
+		db  *sqlx.DB
+		err error
+	)
+	switch {
+	case s.usesPostgres():
+		db, err = connect(ctx, s.config.StartupPolicy, "postgres", func() (*sqlx.DB, error) {
+			return getDatabase(*s.config)
+		})
+	case s.usesSQLite():
+		db, err = connect(ctx, s.config.StartupPolicy, "sqlite", func() (*sqlx.DB, error) {
+			return orderSQLiteRepo.Open(ctx, s.config.SQLitePath)
+		})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.usesRedis() {
+		return db, nil, nil
+	}
+
+	redisDB, err := connect(ctx, s.config.StartupPolicy, "redis", func() (*redis.Client, error) {
+		return getRedis(*s.config)
+	})
+	if err != nil {
+		if s.config.StartupPolicy != StartupDegraded {
+			if db != nil {
+				_ = db.Close()
+			}
+			return nil, nil, err
+		}
+
+		slog.Warn("starting in degraded mode without redis", slog.Any("error", err))
+		return db, nil, nil
+	}
+
+	return db, redisDB, nil
+}
+
+func connect[T any](ctx context.Context, policy, name string, dial func() (T, error)) (T, error) {
+	backoff := startupMinBackoff
+	for {
+		conn, err := dial()
+		if err == nil {
+			return conn, nil
+		}
+		if policy != StartupWait {
+			return conn, fmt.Errorf("%s unavailable: %w", name, err)
+		}
+
+		slog.Warn("waiting for dependency",
+			slog.String("dependency", name), slog.Duration("retry_in", backoff), slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return conn, fmt.Errorf("%s unavailable: %w", name, errors.Join(err, ctx.Err()))
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, startupMaxBackoff)
+	}
+}
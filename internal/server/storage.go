@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"orderservice/internal/repository"
+	"orderservice/internal/repository/cache"
+	inmemoryRepo "orderservice/internal/repository/inmemory"
+	orderPostgresRepo "orderservice/internal/repository/postgres"
+	redisRepo "orderservice/internal/repository/redis"
+	orderSQLiteRepo "orderservice/internal/repository/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	StorageBackendPostgres = "postgres"
+	// StorageBackendMemory keeps orders in process memory. It needs no
+	// infrastructure and loses every order on restart.
+	StorageBackendMemory = "memory"
+	// StorageBackendSQLite stores orders in the SQLITE_PATH file for single
+	// node deployments.
+	StorageBackendSQLite = "sqlite"
+)
+
+const (
+	CacheStoreRedis = "redis"
+	// CacheStoreMemory caches orders in a per-process LRU of CACHE_SIZE
+	// entries.
+	CacheStoreMemory = "memory"
+)
+
+var (
+	ErrUnknownStorageBackend = errors.New("unknown storage backend")
+	ErrUnknownCacheStore     = errors.New("unknown cache store")
+)
+
+func (s *Server) checkStorage() error {
+	switch s.config.StorageBackend {
+	case StorageBackendPostgres, StorageBackendMemory, StorageBackendSQLite:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownStorageBackend, s.config.StorageBackend)
+	}
+
+	switch s.config.CacheStore {
+	case CacheStoreRedis, CacheStoreMemory:
+	default:
+		if s.usesCache() {
+			return fmt.Errorf("%w: %q", ErrUnknownCacheStore, s.config.CacheStore)
+		}
+	}
+
+	return nil
+}
+
+// usesPostgres, usesSQLite and usesRedis report which dependencies the
+// configured backend needs. Redis backs the order cache, idempotency keys,
+// the outbox stream and shared rate limits, all of which only matter for a
+// backend shared by several instances, and the cache when CACHE_STORE asks
+// for it.
+func (s *Server) usesPostgres() bool {
+	return s.config.StorageBackend == StorageBackendPostgres
+}
+
+func (s *Server) usesSQLite() bool {
+	return s.config.StorageBackend == StorageBackendSQLite
+}
+
+func (s *Server) usesRedis() bool {
+	return s.usesPostgres() || (s.usesCache() && s.config.CacheStore == CacheStoreRedis)
+}
+
+// usesCache reports whether orders are cached. The memory backend keeps
+// every order in memory already.
+func (s *Server) usesCache() bool {
+	return s.config.CacheEnable && s.config.StorageBackend != StorageBackendMemory
+}
+
+// newOrderRepository builds the repository for STORAGE_BACKEND, cached when
+// enabled, along with its watcher, which is nil when the backend cannot
+// stream order events.
+func (s *Server) newOrderRepository(
+	db *sqlx.DB,
+	redisDB *redis.Client,
+) (repository.OrderRepository, repository.OrderWatcher, error) {
+	repo, watcher, err := s.newStorageBackend(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.usesCache() {
+		return repo, watcher, nil
+	}
+
+	var store repository.CacheStore
+	var invalidator repository.CacheInvalidator
+	switch s.config.CacheStore {
+	case CacheStoreRedis:
+		// Without Redis the server runs degraded, serving uncached.
+		if redisDB == nil {
+			return repo, watcher, nil
+		}
+		store = redisRepo.NewCacheStore(redisDB)
+
+		if s.config.CacheL1Enable {
+			tiered := cache.NewTieredStore(inmemoryRepo.NewLRUCacheStore(s.config.CacheL1Size), store, &cache.TieredConfig{
+				L1TTL:   s.config.CacheL1TTL,
+				Metrics: s.metrics,
+			})
+			redisInvalidator := redisRepo.NewCacheInvalidator(redisDB, s.config.CacheInvalidation)
+			s.cacheSubscriber = func(ctx context.Context) {
+				redisInvalidator.Subscribe(ctx, tiered.Evict, tiered.EvictAll)
+			}
+			store, invalidator = tiered, redisInvalidator
+		}
+	case CacheStoreMemory:
+		store = inmemoryRepo.NewLRUCacheStore(s.config.CacheSize)
+	}
+
+	cached := cache.NewOrderRepository(repo, store, &cache.Config{
+		TTL:         s.config.CacheTTL,
+		KeyPrefix:   s.config.CacheKeyPrefix,
+		Invalidator: invalidator,
+		Metrics:     s.metrics,
+	})
+
+	return cached, watcher, nil
+}
+
+func (s *Server) newStorageBackend(db *sqlx.DB) (repository.OrderRepository, repository.OrderWatcher, error) {
+	switch s.config.StorageBackend {
+	case StorageBackendPostgres:
+		repo := orderPostgresRepo.NewOrderRepository(db, &orderPostgresRepo.Config{
+			OutboxEnable: s.config.OutboxEnable,
+			Metrics:      s.metrics,
+		})
+		if !s.config.OutboxEnable {
+			return repo, nil, nil
+		}
+
+		s.orderWatcher = orderPostgresRepo.NewOrderWatcher(db, s.config.BuildPostgresConnStr())
+		return repo, s.orderWatcher, nil
+	case StorageBackendMemory:
+		repo := inmemoryRepo.NewOrderRepository()
+		return repo, repo, nil
+	case StorageBackendSQLite:
+		repo := orderSQLiteRepo.NewOrderRepository(db, &orderSQLiteRepo.Config{
+			Metrics: s.metrics,
+		})
+		return repo, repo, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownStorageBackend, s.config.StorageBackend)
+	}
+}
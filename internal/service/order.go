@@ -2,55 +2,223 @@ package service
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"time"
 
+	"orderservice/internal/auth"
 	"orderservice/internal/domain"
 	"orderservice/internal/repository"
 
 	"github.com/google/uuid"
 )
 
+const defaultIdempotencyTTL = 24 * time.Hour
+
 type OrderService struct {
-	repo repository.OrderRepository
+	repo           repository.OrderRepository
+	idempotency    repository.IdempotencyStore
+	watcher        repository.OrderWatcher
+	idempotencyTTL time.Duration
+}
+
+type Config struct {
+	IdempotencyTTL time.Duration
 }
 
-func NewOrderService(repo repository.OrderRepository) *OrderService {
+func NewOrderService(
+	repo repository.OrderRepository,
+	idempotency repository.IdempotencyStore,
+	watcher repository.OrderWatcher,
+	config *Config,
+) *OrderService {
+	if config == nil {
+		config = &Config{
+			IdempotencyTTL: defaultIdempotencyTTL,
+		}
+	}
+
 	return &OrderService{
-		repo: repo,
+		repo:           repo,
+		idempotency:    idempotency,
+		watcher:        watcher,
+		idempotencyTTL: config.IdempotencyTTL,
 	}
 }
 
-func (s *OrderService) Create(ctx context.Context, item string, quantity int32) (*domain.Order, error) {
-	order, err := domain.NewOrder(uuid.New(), item, quantity)
+func (s *OrderService) Create(
+	ctx context.Context,
+	idempotencyKey string,
+	items []domain.LineItem,
+) (*domain.Order, error) {
+	if err := domain.ValidateIdempotencyKey(idempotencyKey); err != nil {
+		return nil, err
+	}
+
+	order, err := domain.NewOrder(uuid.New(), callerID(ctx), items)
+	if err != nil {
+		return nil, err
+	}
+	// Keys are per customer so that callers cannot replay each other's orders.
+	if order.CustomerID != domain.AnyCustomer && idempotencyKey != "" {
+		idempotencyKey = order.CustomerID + ":" + idempotencyKey
+	}
+
+	if idempotencyKey == "" {
+		if err := s.repo.Create(ctx, order); err != nil {
+			return nil, err
+		}
+		return order, nil
+	}
+
+	record := domain.NewIdempotencyRecord(order)
+	existing, err := s.idempotency.Reserve(ctx, idempotencyKey, record, s.idempotencyTTL)
 	if err != nil {
 		return nil, err
 	}
+	if existing != nil {
+		return s.replayCreate(ctx, record, existing)
+	}
 
 	if err := s.repo.Create(ctx, order); err != nil {
+		if releaseErr := s.idempotency.Release(ctx, idempotencyKey); releaseErr != nil {
+			slog.WarnContext(ctx, "release idempotency key failed",
+				slog.String("idempotency_key", idempotencyKey), slog.Any("error", releaseErr))
+		}
 		return nil, err
 	}
+
+	if err := s.idempotency.Complete(ctx, idempotencyKey, record, s.idempotencyTTL); err != nil {
+		slog.WarnContext(ctx, "complete idempotency key failed",
+			slog.String("idempotency_key", idempotencyKey), slog.Any("error", err))
+	}
+
 	return order, nil
 }
 
+func (s *OrderService) replayCreate(
+	ctx context.Context,
+	record domain.IdempotencyRecord,
+	existing *domain.IdempotencyRecord,
+) (*domain.Order, error) {
+	if existing.RequestHash != record.RequestHash {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+
+	order, err := s.repo.Get(ctx, existing.OrderID, customerScope(ctx))
+	if errors.Is(err, domain.ErrOrderNotFound) && !existing.Completed {
+		return nil, domain.ErrIdempotencyKeyInProgress
+	}
+
+	return order, err
+}
+
 func (s *OrderService) Get(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
-	return s.repo.Get(ctx, id)
+	return s.repo.Get(ctx, id, customerScope(ctx))
 }
 
-func (s *OrderService) Update(ctx context.Context, id uuid.UUID, item string, quantity int32) (*domain.Order, error) {
-	order, err := domain.NewOrder(id, item, quantity)
+func (s *OrderService) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	version int64,
+	patch domain.OrderPatch,
+	paths []string,
+) (*domain.Order, error) {
+	mask, err := domain.ParseUpdateMask(paths)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.repo.Update(ctx, order); err != nil {
+	customerID := customerScope(ctx)
+	current, err := s.repo.Get(repository.WithFreshRead(ctx), id, customerID)
+	if err != nil {
 		return nil, err
 	}
-	return order, nil
+	if version != domain.AnyVersion && current.Version != version {
+		return nil, domain.ErrVersionMismatch
+	}
+
+	order := *current
+	if err := order.Apply(patch, mask); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, &order, customerID); err != nil {
+		return nil, err
+	}
+	return &order, nil
 }
 
-func (s *OrderService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+func (s *OrderService) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	return s.repo.Delete(ctx, id, customerScope(ctx), version)
 }
 
-func (s *OrderService) List(ctx context.Context) ([]*domain.Order, error) {
-	return s.repo.List(ctx)
+func (s *OrderService) List(ctx context.Context, query domain.OrderListQuery) (*domain.OrderPage, error) {
+	query.Filter.CustomerID = customerScope(ctx)
+	return s.repo.List(ctx, query)
+}
+
+func (s *OrderService) Transition(ctx context.Context, id uuid.UUID, to domain.OrderStatus) (*domain.Order, error) {
+	customerID := customerScope(ctx)
+	current, err := s.repo.Get(repository.WithFreshRead(ctx), id, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := *current
+	if err := order.Transition(to); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, &order, customerID); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Watch calls fn for every order event matching filter, starting after the
+// event with sequence number after (0 means from now on).
+func (s *OrderService) Watch(
+	ctx context.Context,
+	filter domain.OrderFilter,
+	after int64,
+	fn func(domain.OrderEvent) error,
+) error {
+	if s.watcher == nil {
+		return domain.ErrWatchUnavailable
+	}
+
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	filter.CustomerID = customerScope(ctx)
+
+	return s.watcher.Watch(ctx, after, func(event domain.OrderEvent) error {
+		if !filter.Matches(event.Order) {
+			return nil
+		}
+		return fn(event)
+	})
+}
+
+// callerID returns the customer id of the authenticated caller, or
+// domain.AnyCustomer when authentication is disabled.
+func callerID(ctx context.Context) string {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.AnyCustomer
+	}
+
+	return principal.Subject
+}
+
+// customerScope returns the customer whose orders the caller may access.
+// Admins and unauthenticated deployments see every order.
+func customerScope(ctx context.Context) string {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.IsAdmin() {
+		return domain.AnyCustomer
+	}
+
+	return principal.Subject
 }
@@ -9,6 +9,8 @@ package order
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,18 +23,136 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type Order struct {
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
+	OrderStatus_ORDER_STATUS_PENDING     OrderStatus = 1
+	OrderStatus_ORDER_STATUS_CONFIRMED   OrderStatus = 2
+	OrderStatus_ORDER_STATUS_PAID        OrderStatus = 3
+	OrderStatus_ORDER_STATUS_SHIPPED     OrderStatus = 4
+	OrderStatus_ORDER_STATUS_DELIVERED   OrderStatus = 5
+	OrderStatus_ORDER_STATUS_CANCELLED   OrderStatus = 6
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_PENDING",
+		2: "ORDER_STATUS_CONFIRMED",
+		3: "ORDER_STATUS_PAID",
+		4: "ORDER_STATUS_SHIPPED",
+		5: "ORDER_STATUS_DELIVERED",
+		6: "ORDER_STATUS_CANCELLED",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED": 0,
+		"ORDER_STATUS_PENDING":     1,
+		"ORDER_STATUS_CONFIRMED":   2,
+		"ORDER_STATUS_PAID":        3,
+		"ORDER_STATUS_SHIPPED":     4,
+		"ORDER_STATUS_DELIVERED":   5,
+		"ORDER_STATUS_CANCELLED":   6,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_order_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_api_proto_order_proto_enumTypes[0]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{0}
+}
+
+type LineItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Item          string                 `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Item          string                 `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineItem) Reset() {
+	*x = LineItem{}
+	mi := &file_api_proto_order_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineItem) ProtoMessage() {}
+
+func (x *LineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineItem.ProtoReflect.Descriptor instead.
+func (*LineItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LineItem) GetItem() string {
+	if x != nil {
+		return x.Item
+	}
+	return ""
+}
+
+func (x *LineItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type Order struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status     OrderStatus            `protobuf:"varint,4,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
+	Items      []*LineItem            `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// Changes on every write; echo it back in update and delete requests.
+	Etag string `protobuf:"bytes,7,opt,name=etag,proto3" json:"etag,omitempty"`
+	// Subject of the caller that created the order; output only.
+	CustomerId    string `protobuf:"bytes,8,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
 	*x = Order{}
-	mi := &file_api_proto_order_proto_msgTypes[0]
+	mi := &file_api_proto_order_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -44,7 +164,7 @@ func (x *Order) String() string {
 func (*Order) ProtoMessage() {}
 
 func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[0]
+	mi := &file_api_proto_order_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,7 +177,7 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Order.ProtoReflect.Descriptor instead.
 func (*Order) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{0}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Order) GetId() string {
@@ -67,31 +187,55 @@ func (x *Order) GetId() string {
 	return ""
 }
 
-func (x *Order) GetItem() string {
+func (x *Order) GetStatus() OrderStatus {
 	if x != nil {
-		return x.Item
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *Order) GetItems() []*LineItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Order) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Order) GetEtag() string {
+	if x != nil {
+		return x.Etag
 	}
 	return ""
 }
 
-func (x *Order) GetQuantity() int32 {
+func (x *Order) GetCustomerId() string {
 	if x != nil {
-		return x.Quantity
+		return x.CustomerId
 	}
-	return 0
+	return ""
 }
 
 type CreateOrderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Item          string                 `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
-	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*LineItem            `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	// Replays with the same key return the original order instead of creating
+	// a new one. May also be sent as "idempotency-key" metadata or the
+	// Idempotency-Key HTTP header.
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CreateOrderRequest) Reset() {
 	*x = CreateOrderRequest{}
-	mi := &file_api_proto_order_proto_msgTypes[1]
+	mi := &file_api_proto_order_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -103,7 +247,7 @@ func (x *CreateOrderRequest) String() string {
 func (*CreateOrderRequest) ProtoMessage() {}
 
 func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[1]
+	mi := &file_api_proto_order_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -116,21 +260,21 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{1}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *CreateOrderRequest) GetItem() string {
+func (x *CreateOrderRequest) GetItems() []*LineItem {
 	if x != nil {
-		return x.Item
+		return x.Items
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateOrderRequest) GetQuantity() int32 {
+func (x *CreateOrderRequest) GetIdempotencyKey() string {
 	if x != nil {
-		return x.Quantity
+		return x.IdempotencyKey
 	}
-	return 0
+	return ""
 }
 
 type CreateOrderResponse struct {
@@ -142,7 +286,7 @@ type CreateOrderResponse struct {
 
 func (x *CreateOrderResponse) Reset() {
 	*x = CreateOrderResponse{}
-	mi := &file_api_proto_order_proto_msgTypes[2]
+	mi := &file_api_proto_order_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -154,7 +298,7 @@ func (x *CreateOrderResponse) String() string {
 func (*CreateOrderResponse) ProtoMessage() {}
 
 func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[2]
+	mi := &file_api_proto_order_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -167,7 +311,7 @@ func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
 func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{2}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateOrderResponse) GetId() string {
@@ -186,7 +330,7 @@ type GetOrderRequest struct {
 
 func (x *GetOrderRequest) Reset() {
 	*x = GetOrderRequest{}
-	mi := &file_api_proto_order_proto_msgTypes[3]
+	mi := &file_api_proto_order_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -198,7 +342,7 @@ func (x *GetOrderRequest) String() string {
 func (*GetOrderRequest) ProtoMessage() {}
 
 func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[3]
+	mi := &file_api_proto_order_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -211,7 +355,7 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{3}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetOrderRequest) GetId() string {
@@ -230,7 +374,7 @@ type GetOrderResponse struct {
 
 func (x *GetOrderResponse) Reset() {
 	*x = GetOrderResponse{}
-	mi := &file_api_proto_order_proto_msgTypes[4]
+	mi := &file_api_proto_order_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -242,7 +386,7 @@ func (x *GetOrderResponse) String() string {
 func (*GetOrderResponse) ProtoMessage() {}
 
 func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[4]
+	mi := &file_api_proto_order_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -255,7 +399,7 @@ func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
 func (*GetOrderResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{4}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetOrderResponse) GetOrder() *Order {
@@ -266,17 +410,23 @@ func (x *GetOrderResponse) GetOrder() *Order {
 }
 
 type UpdateOrderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Item          string                 `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Items []*LineItem            `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	// When set, the update fails with ABORTED unless it matches the current etag.
+	Etag string `protobuf:"bytes,5,opt,name=etag,proto3" json:"etag,omitempty"`
+	// Fields to change. "items" replaces the whole list; "items.quantity"
+	// merges items by name instead, setting the quantity of matching line
+	// items and appending the others, so a single quantity can change without
+	// resending the order. An empty mask replaces every updatable field.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,6,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateOrderRequest) Reset() {
 	*x = UpdateOrderRequest{}
-	mi := &file_api_proto_order_proto_msgTypes[5]
+	mi := &file_api_proto_order_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -288,7 +438,7 @@ func (x *UpdateOrderRequest) String() string {
 func (*UpdateOrderRequest) ProtoMessage() {}
 
 func (x *UpdateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[5]
+	mi := &file_api_proto_order_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -301,7 +451,7 @@ func (x *UpdateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOrderRequest.ProtoReflect.Descriptor instead.
 func (*UpdateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{5}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateOrderRequest) GetId() string {
@@ -311,18 +461,25 @@ func (x *UpdateOrderRequest) GetId() string {
 	return ""
 }
 
-func (x *UpdateOrderRequest) GetItem() string {
+func (x *UpdateOrderRequest) GetItems() []*LineItem {
 	if x != nil {
-		return x.Item
+		return x.Items
+	}
+	return nil
+}
+
+func (x *UpdateOrderRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
 	}
 	return ""
 }
 
-func (x *UpdateOrderRequest) GetQuantity() int32 {
+func (x *UpdateOrderRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
 	if x != nil {
-		return x.Quantity
+		return x.UpdateMask
 	}
-	return 0
+	return nil
 }
 
 type UpdateOrderResponse struct {
@@ -334,7 +491,7 @@ type UpdateOrderResponse struct {
 
 func (x *UpdateOrderResponse) Reset() {
 	*x = UpdateOrderResponse{}
-	mi := &file_api_proto_order_proto_msgTypes[6]
+	mi := &file_api_proto_order_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -346,7 +503,7 @@ func (x *UpdateOrderResponse) String() string {
 func (*UpdateOrderResponse) ProtoMessage() {}
 
 func (x *UpdateOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[6]
+	mi := &file_api_proto_order_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -359,7 +516,7 @@ func (x *UpdateOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOrderResponse.ProtoReflect.Descriptor instead.
 func (*UpdateOrderResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{6}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UpdateOrderResponse) GetOrder() *Order {
@@ -370,15 +527,17 @@ func (x *UpdateOrderResponse) GetOrder() *Order {
 }
 
 type DeleteOrderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// When set, the delete fails with ABORTED unless it matches the current etag.
+	Etag          string `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteOrderRequest) Reset() {
 	*x = DeleteOrderRequest{}
-	mi := &file_api_proto_order_proto_msgTypes[7]
+	mi := &file_api_proto_order_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -390,7 +549,7 @@ func (x *DeleteOrderRequest) String() string {
 func (*DeleteOrderRequest) ProtoMessage() {}
 
 func (x *DeleteOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[7]
+	mi := &file_api_proto_order_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -403,7 +562,7 @@ func (x *DeleteOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteOrderRequest.ProtoReflect.Descriptor instead.
 func (*DeleteOrderRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DeleteOrderRequest) GetId() string {
@@ -413,6 +572,13 @@ func (x *DeleteOrderRequest) GetId() string {
 	return ""
 }
 
+func (x *DeleteOrderRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
 type DeleteOrderResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -422,7 +588,7 @@ type DeleteOrderResponse struct {
 
 func (x *DeleteOrderResponse) Reset() {
 	*x = DeleteOrderResponse{}
-	mi := &file_api_proto_order_proto_msgTypes[8]
+	mi := &file_api_proto_order_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -434,7 +600,7 @@ func (x *DeleteOrderResponse) String() string {
 func (*DeleteOrderResponse) ProtoMessage() {}
 
 func (x *DeleteOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[8]
+	mi := &file_api_proto_order_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -447,7 +613,7 @@ func (x *DeleteOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteOrderResponse.ProtoReflect.Descriptor instead.
 func (*DeleteOrderResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{8}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *DeleteOrderResponse) GetSuccess() bool {
@@ -457,15 +623,108 @@ func (x *DeleteOrderResponse) GetSuccess() bool {
 	return false
 }
 
+type OrderFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Matches orders containing a line item with exactly this name.
+	Item string `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	// Bounds on the total quantity across all line items, inclusive.
+	MinQuantity *int32        `protobuf:"varint,2,opt,name=min_quantity,json=minQuantity,proto3,oneof" json:"min_quantity,omitempty"`
+	MaxQuantity *int32        `protobuf:"varint,3,opt,name=max_quantity,json=maxQuantity,proto3,oneof" json:"max_quantity,omitempty"`
+	Statuses    []OrderStatus `protobuf:"varint,4,rep,packed,name=statuses,proto3,enum=order.OrderStatus" json:"statuses,omitempty"`
+	// Inclusive lower and exclusive upper bound on create_time.
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderFilter) Reset() {
+	*x = OrderFilter{}
+	mi := &file_api_proto_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFilter) ProtoMessage() {}
+
+func (x *OrderFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFilter.ProtoReflect.Descriptor instead.
+func (*OrderFilter) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OrderFilter) GetItem() string {
+	if x != nil {
+		return x.Item
+	}
+	return ""
+}
+
+func (x *OrderFilter) GetMinQuantity() int32 {
+	if x != nil && x.MinQuantity != nil {
+		return *x.MinQuantity
+	}
+	return 0
+}
+
+func (x *OrderFilter) GetMaxQuantity() int32 {
+	if x != nil && x.MaxQuantity != nil {
+		return *x.MaxQuantity
+	}
+	return 0
+}
+
+func (x *OrderFilter) GetStatuses() []OrderStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *OrderFilter) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *OrderFilter) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
 type ListOrdersRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to 50, values above 1000 are coerced to 1000.
+	PageSize  int32        `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string       `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Filter    *OrderFilter `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	// "create_time" (default) or "id", optionally followed by "asc" or "desc".
+	OrderBy       string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListOrdersRequest) Reset() {
 	*x = ListOrdersRequest{}
-	mi := &file_api_proto_order_proto_msgTypes[9]
+	mi := &file_api_proto_order_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -477,7 +736,7 @@ func (x *ListOrdersRequest) String() string {
 func (*ListOrdersRequest) ProtoMessage() {}
 
 func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[9]
+	mi := &file_api_proto_order_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -490,19 +749,48 @@ func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
 func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{9}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListOrdersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetFilter() *OrderFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListOrdersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
 }
 
 type ListOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListOrdersResponse) Reset() {
 	*x = ListOrdersResponse{}
-	mi := &file_api_proto_order_proto_msgTypes[10]
+	mi := &file_api_proto_order_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -514,7 +802,7 @@ func (x *ListOrdersResponse) String() string {
 func (*ListOrdersResponse) ProtoMessage() {}
 
 func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_proto_msgTypes[10]
+	mi := &file_api_proto_order_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -527,7 +815,7 @@ func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
 func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_proto_rawDescGZIP(), []int{10}
+	return file_api_proto_order_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ListOrdersResponse) GetOrders() []*Order {
@@ -537,44 +825,573 @@ func (x *ListOrdersResponse) GetOrders() []*Order {
 	return nil
 }
 
-var File_api_proto_order_proto protoreflect.FileDescriptor
+func (x *ListOrdersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
 
-const file_api_proto_order_proto_rawDesc = "" +
+type TransitionOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitionOrderRequest) Reset() {
+	*x = TransitionOrderRequest{}
+	mi := &file_api_proto_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionOrderRequest) ProtoMessage() {}
+
+func (x *TransitionOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionOrderRequest.ProtoReflect.Descriptor instead.
+func (*TransitionOrderRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TransitionOrderRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TransitionOrderRequest) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+type TransitionOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitionOrderResponse) Reset() {
+	*x = TransitionOrderResponse{}
+	mi := &file_api_proto_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionOrderResponse) ProtoMessage() {}
+
+func (x *TransitionOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionOrderResponse.ProtoReflect.Descriptor instead.
+func (*TransitionOrderResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TransitionOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type WatchOrdersRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *OrderFilter           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Resume after the event that carried this token instead of starting from
+	// the next change. Over HTTP the Last-Event-ID header is honoured as well.
+	ResumeToken   string `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchOrdersRequest) Reset() {
+	*x = WatchOrdersRequest{}
+	mi := &file_api_proto_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOrdersRequest) ProtoMessage() {}
+
+func (x *WatchOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOrdersRequest.ProtoReflect.Descriptor instead.
+func (*WatchOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchOrdersRequest) GetFilter() *OrderFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *WatchOrdersRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type WatchOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *OrderEvent            `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchOrdersResponse) Reset() {
+	*x = WatchOrdersResponse{}
+	mi := &file_api_proto_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOrdersResponse) ProtoMessage() {}
+
+func (x *WatchOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOrdersResponse.ProtoReflect.Descriptor instead.
+func (*WatchOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WatchOrdersResponse) GetEvent() *OrderEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *WatchOrdersResponse) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// OrderEvent is published to the order events stream for every committed
+// change. Consumers must tolerate duplicates: delivery is at-least-once.
+type OrderEvent struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	EventId   string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	OccurTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occur_time,json=occurTime,proto3" json:"occur_time,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*OrderEvent_Created
+	//	*OrderEvent_Updated
+	//	*OrderEvent_Deleted
+	Payload       isOrderEvent_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderEvent) Reset() {
+	*x = OrderEvent{}
+	mi := &file_api_proto_order_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderEvent) ProtoMessage() {}
+
+func (x *OrderEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderEvent.ProtoReflect.Descriptor instead.
+func (*OrderEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *OrderEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetOccurTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurTime
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetPayload() isOrderEvent_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetCreated() *OrderCreated {
+	if x != nil {
+		if x, ok := x.Payload.(*OrderEvent_Created); ok {
+			return x.Created
+		}
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetUpdated() *OrderUpdated {
+	if x != nil {
+		if x, ok := x.Payload.(*OrderEvent_Updated); ok {
+			return x.Updated
+		}
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetDeleted() *OrderDeleted {
+	if x != nil {
+		if x, ok := x.Payload.(*OrderEvent_Deleted); ok {
+			return x.Deleted
+		}
+	}
+	return nil
+}
+
+type isOrderEvent_Payload interface {
+	isOrderEvent_Payload()
+}
+
+type OrderEvent_Created struct {
+	Created *OrderCreated `protobuf:"bytes,3,opt,name=created,proto3,oneof"`
+}
+
+type OrderEvent_Updated struct {
+	Updated *OrderUpdated `protobuf:"bytes,4,opt,name=updated,proto3,oneof"`
+}
+
+type OrderEvent_Deleted struct {
+	Deleted *OrderDeleted `protobuf:"bytes,5,opt,name=deleted,proto3,oneof"`
+}
+
+func (*OrderEvent_Created) isOrderEvent_Payload() {}
+
+func (*OrderEvent_Updated) isOrderEvent_Payload() {}
+
+func (*OrderEvent_Deleted) isOrderEvent_Payload() {}
+
+type OrderCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCreated) Reset() {
+	*x = OrderCreated{}
+	mi := &file_api_proto_order_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCreated) ProtoMessage() {}
+
+func (x *OrderCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCreated.ProtoReflect.Descriptor instead.
+func (*OrderCreated) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *OrderCreated) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type OrderUpdated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderUpdated) Reset() {
+	*x = OrderUpdated{}
+	mi := &file_api_proto_order_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderUpdated) ProtoMessage() {}
+
+func (x *OrderUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderUpdated.ProtoReflect.Descriptor instead.
+func (*OrderUpdated) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *OrderUpdated) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type OrderDeleted struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Last state of the order before it was deleted.
+	Order         *Order `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderDeleted) Reset() {
+	*x = OrderDeleted{}
+	mi := &file_api_proto_order_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderDeleted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderDeleted) ProtoMessage() {}
+
+func (x *OrderDeleted) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderDeleted.ProtoReflect.Descriptor instead.
+func (*OrderDeleted) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OrderDeleted) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrderDeleted) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+var File_api_proto_order_proto protoreflect.FileDescriptor
+
+const file_api_proto_order_proto_rawDesc = "" +
 	"\n" +
-	"\x15api/proto/order.proto\x12\x05order\"G\n" +
-	"\x05Order\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04item\x18\x02 \x01(\tR\x04item\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\"D\n" +
-	"\x12CreateOrderRequest\x12\x12\n" +
+	"\x15api/proto/order.proto\x12\x05order\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\":\n" +
+	"\bLineItem\x12\x12\n" +
 	"\x04item\x18\x01 \x01(\tR\x04item\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x05R\bquantity\"%\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"\xf8\x01\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x12.order.OrderStatusR\x06status\x12%\n" +
+	"\x05items\x18\x05 \x03(\v2\x0f.order.LineItemR\x05items\x12;\n" +
+	"\vcreate_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x12\x12\n" +
+	"\x04etag\x18\a \x01(\tR\x04etag\x12\x1f\n" +
+	"\vcustomer_id\x18\b \x01(\tR\n" +
+	"customerIdJ\x04\b\x02\x10\x03J\x04\b\x03\x10\x04R\x04itemR\bquantity\"\x80\x01\n" +
+	"\x12CreateOrderRequest\x12%\n" +
+	"\x05items\x18\x03 \x03(\v2\x0f.order.LineItemR\x05items\x12'\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKeyJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03R\x04itemR\bquantity\"%\n" +
 	"\x13CreateOrderResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"!\n" +
 	"\x0fGetOrderRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"6\n" +
 	"\x10GetOrderResponse\x12\"\n" +
-	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"T\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"\xb8\x01\n" +
 	"\x12UpdateOrderRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04item\x18\x02 \x01(\tR\x04item\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\"9\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x05items\x18\x04 \x03(\v2\x0f.order.LineItemR\x05items\x12\x12\n" +
+	"\x04etag\x18\x05 \x01(\tR\x04etag\x12;\n" +
+	"\vupdate_mask\x18\x06 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMaskJ\x04\b\x02\x10\x03J\x04\b\x03\x10\x04R\x04itemR\bquantity\"9\n" +
 	"\x13UpdateOrderResponse\x12\"\n" +
-	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"$\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"8\n" +
 	"\x12DeleteOrderRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04etag\x18\x02 \x01(\tR\x04etag\"/\n" +
 	"\x13DeleteOrderResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x13\n" +
-	"\x11ListOrdersRequest\":\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xc7\x02\n" +
+	"\vOrderFilter\x12\x12\n" +
+	"\x04item\x18\x01 \x01(\tR\x04item\x12&\n" +
+	"\fmin_quantity\x18\x02 \x01(\x05H\x00R\vminQuantity\x88\x01\x01\x12&\n" +
+	"\fmax_quantity\x18\x03 \x01(\x05H\x01R\vmaxQuantity\x88\x01\x01\x12.\n" +
+	"\bstatuses\x18\x04 \x03(\x0e2\x12.order.OrderStatusR\bstatuses\x12?\n" +
+	"\rcreated_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBeforeB\x0f\n" +
+	"\r_min_quantityB\x0f\n" +
+	"\r_max_quantity\"\x96\x01\n" +
+	"\x11ListOrdersRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12*\n" +
+	"\x06filter\x18\x03 \x01(\v2\x12.order.OrderFilterR\x06filter\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\"b\n" +
 	"\x12ListOrdersResponse\x12$\n" +
-	"\x06orders\x18\x01 \x03(\v2\f.order.OrderR\x06orders2\xe0\x02\n" +
+	"\x06orders\x18\x01 \x03(\v2\f.order.OrderR\x06orders\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"T\n" +
+	"\x16TransitionOrderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\x06status\"=\n" +
+	"\x17TransitionOrderResponse\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"c\n" +
+	"\x12WatchOrdersRequest\x12*\n" +
+	"\x06filter\x18\x01 \x01(\v2\x12.order.OrderFilterR\x06filter\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\"a\n" +
+	"\x13WatchOrdersResponse\x12'\n" +
+	"\x05event\x18\x01 \x01(\v2\x11.order.OrderEventR\x05event\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\"\x80\x02\n" +
+	"\n" +
+	"OrderEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x129\n" +
+	"\n" +
+	"occur_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\toccurTime\x12/\n" +
+	"\acreated\x18\x03 \x01(\v2\x13.order.OrderCreatedH\x00R\acreated\x12/\n" +
+	"\aupdated\x18\x04 \x01(\v2\x13.order.OrderUpdatedH\x00R\aupdated\x12/\n" +
+	"\adeleted\x18\x05 \x01(\v2\x13.order.OrderDeletedH\x00R\adeletedB\t\n" +
+	"\apayload\"2\n" +
+	"\fOrderCreated\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"2\n" +
+	"\fOrderUpdated\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"B\n" +
+	"\fOrderDeleted\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\x05order\x18\x02 \x01(\v2\f.order.OrderR\x05order*\xca\x01\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ORDER_STATUS_PENDING\x10\x01\x12\x1a\n" +
+	"\x16ORDER_STATUS_CONFIRMED\x10\x02\x12\x15\n" +
+	"\x11ORDER_STATUS_PAID\x10\x03\x12\x18\n" +
+	"\x14ORDER_STATUS_SHIPPED\x10\x04\x12\x1a\n" +
+	"\x16ORDER_STATUS_DELIVERED\x10\x05\x12\x1a\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x062\xfa\x03\n" +
 	"\fOrderService\x12D\n" +
 	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\x1a.order.CreateOrderResponse\x12;\n" +
 	"\bGetOrder\x12\x16.order.GetOrderRequest\x1a\x17.order.GetOrderResponse\x12D\n" +
 	"\vUpdateOrder\x12\x19.order.UpdateOrderRequest\x1a\x1a.order.UpdateOrderResponse\x12D\n" +
 	"\vDeleteOrder\x12\x19.order.DeleteOrderRequest\x1a\x1a.order.DeleteOrderResponse\x12A\n" +
 	"\n" +
-	"ListOrders\x12\x18.order.ListOrdersRequest\x1a\x19.order.ListOrdersResponseB\x0fZ\rpkg/api/orderb\x06proto3"
+	"ListOrders\x12\x18.order.ListOrdersRequest\x1a\x19.order.ListOrdersResponse\x12P\n" +
+	"\x0fTransitionOrder\x12\x1d.order.TransitionOrderRequest\x1a\x1e.order.TransitionOrderResponse\x12F\n" +
+	"\vWatchOrders\x12\x19.order.WatchOrdersRequest\x1a\x1a.order.WatchOrdersResponse0\x01B\x0fZ\rpkg/api/orderb\x06proto3"
 
 var (
 	file_api_proto_order_proto_rawDescOnce sync.Once
@@ -588,39 +1405,78 @@ func file_api_proto_order_proto_rawDescGZIP() []byte {
 	return file_api_proto_order_proto_rawDescData
 }
 
-var file_api_proto_order_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_api_proto_order_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_proto_order_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_api_proto_order_proto_goTypes = []any{
-	(*Order)(nil),               // 0: order.Order
-	(*CreateOrderRequest)(nil),  // 1: order.CreateOrderRequest
-	(*CreateOrderResponse)(nil), // 2: order.CreateOrderResponse
-	(*GetOrderRequest)(nil),     // 3: order.GetOrderRequest
-	(*GetOrderResponse)(nil),    // 4: order.GetOrderResponse
-	(*UpdateOrderRequest)(nil),  // 5: order.UpdateOrderRequest
-	(*UpdateOrderResponse)(nil), // 6: order.UpdateOrderResponse
-	(*DeleteOrderRequest)(nil),  // 7: order.DeleteOrderRequest
-	(*DeleteOrderResponse)(nil), // 8: order.DeleteOrderResponse
-	(*ListOrdersRequest)(nil),   // 9: order.ListOrdersRequest
-	(*ListOrdersResponse)(nil),  // 10: order.ListOrdersResponse
+	(OrderStatus)(0),                // 0: order.OrderStatus
+	(*LineItem)(nil),                // 1: order.LineItem
+	(*Order)(nil),                   // 2: order.Order
+	(*CreateOrderRequest)(nil),      // 3: order.CreateOrderRequest
+	(*CreateOrderResponse)(nil),     // 4: order.CreateOrderResponse
+	(*GetOrderRequest)(nil),         // 5: order.GetOrderRequest
+	(*GetOrderResponse)(nil),        // 6: order.GetOrderResponse
+	(*UpdateOrderRequest)(nil),      // 7: order.UpdateOrderRequest
+	(*UpdateOrderResponse)(nil),     // 8: order.UpdateOrderResponse
+	(*DeleteOrderRequest)(nil),      // 9: order.DeleteOrderRequest
+	(*DeleteOrderResponse)(nil),     // 10: order.DeleteOrderResponse
+	(*OrderFilter)(nil),             // 11: order.OrderFilter
+	(*ListOrdersRequest)(nil),       // 12: order.ListOrdersRequest
+	(*ListOrdersResponse)(nil),      // 13: order.ListOrdersResponse
+	(*TransitionOrderRequest)(nil),  // 14: order.TransitionOrderRequest
+	(*TransitionOrderResponse)(nil), // 15: order.TransitionOrderResponse
+	(*WatchOrdersRequest)(nil),      // 16: order.WatchOrdersRequest
+	(*WatchOrdersResponse)(nil),     // 17: order.WatchOrdersResponse
+	(*OrderEvent)(nil),              // 18: order.OrderEvent
+	(*OrderCreated)(nil),            // 19: order.OrderCreated
+	(*OrderUpdated)(nil),            // 20: order.OrderUpdated
+	(*OrderDeleted)(nil),            // 21: order.OrderDeleted
+	(*timestamppb.Timestamp)(nil),   // 22: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),   // 23: google.protobuf.FieldMask
 }
 var file_api_proto_order_proto_depIdxs = []int32{
-	0,  // 0: order.GetOrderResponse.order:type_name -> order.Order
-	0,  // 1: order.UpdateOrderResponse.order:type_name -> order.Order
-	0,  // 2: order.ListOrdersResponse.orders:type_name -> order.Order
-	1,  // 3: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
-	3,  // 4: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
-	5,  // 5: order.OrderService.UpdateOrder:input_type -> order.UpdateOrderRequest
-	7,  // 6: order.OrderService.DeleteOrder:input_type -> order.DeleteOrderRequest
-	9,  // 7: order.OrderService.ListOrders:input_type -> order.ListOrdersRequest
-	2,  // 8: order.OrderService.CreateOrder:output_type -> order.CreateOrderResponse
-	4,  // 9: order.OrderService.GetOrder:output_type -> order.GetOrderResponse
-	6,  // 10: order.OrderService.UpdateOrder:output_type -> order.UpdateOrderResponse
-	8,  // 11: order.OrderService.DeleteOrder:output_type -> order.DeleteOrderResponse
-	10, // 12: order.OrderService.ListOrders:output_type -> order.ListOrdersResponse
-	8,  // [8:13] is the sub-list for method output_type
-	3,  // [3:8] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	0,  // 0: order.Order.status:type_name -> order.OrderStatus
+	1,  // 1: order.Order.items:type_name -> order.LineItem
+	22, // 2: order.Order.create_time:type_name -> google.protobuf.Timestamp
+	1,  // 3: order.CreateOrderRequest.items:type_name -> order.LineItem
+	2,  // 4: order.GetOrderResponse.order:type_name -> order.Order
+	1,  // 5: order.UpdateOrderRequest.items:type_name -> order.LineItem
+	23, // 6: order.UpdateOrderRequest.update_mask:type_name -> google.protobuf.FieldMask
+	2,  // 7: order.UpdateOrderResponse.order:type_name -> order.Order
+	0,  // 8: order.OrderFilter.statuses:type_name -> order.OrderStatus
+	22, // 9: order.OrderFilter.created_after:type_name -> google.protobuf.Timestamp
+	22, // 10: order.OrderFilter.created_before:type_name -> google.protobuf.Timestamp
+	11, // 11: order.ListOrdersRequest.filter:type_name -> order.OrderFilter
+	2,  // 12: order.ListOrdersResponse.orders:type_name -> order.Order
+	0,  // 13: order.TransitionOrderRequest.status:type_name -> order.OrderStatus
+	2,  // 14: order.TransitionOrderResponse.order:type_name -> order.Order
+	11, // 15: order.WatchOrdersRequest.filter:type_name -> order.OrderFilter
+	18, // 16: order.WatchOrdersResponse.event:type_name -> order.OrderEvent
+	22, // 17: order.OrderEvent.occur_time:type_name -> google.protobuf.Timestamp
+	19, // 18: order.OrderEvent.created:type_name -> order.OrderCreated
+	20, // 19: order.OrderEvent.updated:type_name -> order.OrderUpdated
+	21, // 20: order.OrderEvent.deleted:type_name -> order.OrderDeleted
+	2,  // 21: order.OrderCreated.order:type_name -> order.Order
+	2,  // 22: order.OrderUpdated.order:type_name -> order.Order
+	2,  // 23: order.OrderDeleted.order:type_name -> order.Order
+	3,  // 24: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
+	5,  // 25: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
+	7,  // 26: order.OrderService.UpdateOrder:input_type -> order.UpdateOrderRequest
+	9,  // 27: order.OrderService.DeleteOrder:input_type -> order.DeleteOrderRequest
+	12, // 28: order.OrderService.ListOrders:input_type -> order.ListOrdersRequest
+	14, // 29: order.OrderService.TransitionOrder:input_type -> order.TransitionOrderRequest
+	16, // 30: order.OrderService.WatchOrders:input_type -> order.WatchOrdersRequest
+	4,  // 31: order.OrderService.CreateOrder:output_type -> order.CreateOrderResponse
+	6,  // 32: order.OrderService.GetOrder:output_type -> order.GetOrderResponse
+	8,  // 33: order.OrderService.UpdateOrder:output_type -> order.UpdateOrderResponse
+	10, // 34: order.OrderService.DeleteOrder:output_type -> order.DeleteOrderResponse
+	13, // 35: order.OrderService.ListOrders:output_type -> order.ListOrdersResponse
+	15, // 36: order.OrderService.TransitionOrder:output_type -> order.TransitionOrderResponse
+	17, // 37: order.OrderService.WatchOrders:output_type -> order.WatchOrdersResponse
+	31, // [31:38] is the sub-list for method output_type
+	24, // [24:31] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_order_proto_init() }
@@ -628,18 +1484,25 @@ func file_api_proto_order_proto_init() {
 	if File_api_proto_order_proto != nil {
 		return
 	}
+	file_api_proto_order_proto_msgTypes[10].OneofWrappers = []any{}
+	file_api_proto_order_proto_msgTypes[17].OneofWrappers = []any{
+		(*OrderEvent_Created)(nil),
+		(*OrderEvent_Updated)(nil),
+		(*OrderEvent_Deleted)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_order_proto_rawDesc), len(file_api_proto_order_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      1,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_proto_order_proto_goTypes,
 		DependencyIndexes: file_api_proto_order_proto_depIdxs,
+		EnumInfos:         file_api_proto_order_proto_enumTypes,
 		MessageInfos:      file_api_proto_order_proto_msgTypes,
 	}.Build()
 	File_api_proto_order_proto = out.File